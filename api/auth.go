@@ -0,0 +1,152 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"errors"
+	"net/http"
+)
+
+// An Authenticator adds the credentials of a user to the requests made by a
+// Client. Only the headers of the authentication method in use are sent.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/README.html#authentication
+type Authenticator interface {
+	// Authenticate adds credentials to req. It is called before every
+	// attempt to send a request, retries included.
+	Authenticate(req *http.Request) error
+}
+
+// PrivateToken authenticates requests using a private or personal access
+// token, which is sent in the PRIVATE-TOKEN header.
+type PrivateToken string
+
+// Authenticate implements the Authenticator interface.
+func (t PrivateToken) Authenticate(req *http.Request) error {
+	req.Header.Set("PRIVATE-TOKEN", string(t))
+	return nil
+}
+
+// JobToken authenticates requests using the token of a CI job, which is sent
+// in the JOB-TOKEN header.
+type JobToken string
+
+// Authenticate implements the Authenticator interface.
+func (t JobToken) Authenticate(req *http.Request) error {
+	req.Header.Set("JOB-TOKEN", string(t))
+	return nil
+}
+
+// A TokenSource returns OAuth2 access tokens. It is called for every request,
+// so implementations should cache the token and refresh it when it expires,
+// like the token sources of golang.org/x/oauth2 do.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// TokenSourceFunc is an adapter to allow the use of ordinary functions as
+// TokenSource. An oauth2.TokenSource can be adapted like this:
+//
+//	gitlab.TokenSourceFunc(func() (string, error) {
+//		t, err := ts.Token()
+//		if err != nil {
+//			return "", err
+//		}
+//		return t.AccessToken, nil
+//	})
+type TokenSourceFunc func() (string, error)
+
+// Token calls f().
+func (f TokenSourceFunc) Token() (string, error) {
+	return f()
+}
+
+// OAuthToken authenticates requests using OAuth2 bearer tokens. The tokens are
+// taken from a TokenSource, which takes care of refreshing them.
+type OAuthToken struct {
+	Source TokenSource
+}
+
+// OAuth returns an OAuthToken authenticating with the tokens of src.
+func OAuth(src TokenSource) *OAuthToken {
+	return &OAuthToken{Source: src}
+}
+
+// StaticOAuth returns an OAuthToken which always authenticates with token.
+func StaticOAuth(token string) *OAuthToken {
+	return OAuth(TokenSourceFunc(func() (string, error) {
+		return token, nil
+	}))
+}
+
+// Authenticate implements the Authenticator interface.
+func (t *OAuthToken) Authenticate(req *http.Request) error {
+	token, err := t.Source.Token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// SetAuth sets the Authenticator used to authenticate requests. A nil
+// Authenticator sends requests without credentials, which is useful when the
+// HTTP client of the Client already authenticates them (e.g. an HTTP client
+// created by golang.org/x/oauth2).
+func (c *Client) SetAuth(a Authenticator) {
+	c.auth = a
+}
+
+// Auth returns the Authenticator used to authenticate requests.
+func (c *Client) Auth() Authenticator {
+	return c.auth
+}
+
+// authenticate adds the credentials of c to req. It is called before every
+// attempt to send req, so retries don't resend an expired token.
+func (c *Client) authenticate(req *http.Request) error {
+	if c.auth == nil {
+		return nil
+	}
+	return c.auth.Authenticate(req)
+}
+
+// Login logs in using a login (or email) and password, and authenticates all
+// subsequent requests of the client with the private token of the session.
+// Only supported by API v3, as API v4 removed sessions: with API v4, use a
+// personal access token or OAuth instead.
+//
+// GitLab API docs: http://doc.gitlab.com/ce/api/session.html#session
+func (s *SessionService) Login(
+	opt *GetSessionOptions,
+	options ...OptionFunc) (*Session, *Response, error) {
+	if s.client.apiVersion != APIVersion3 {
+		return nil, nil, errors.New("sessions are only supported by API v3")
+	}
+
+	session, resp, err := s.GetSession(opt, options...)
+	if err != nil {
+		return nil, resp, err
+	}
+	if session.PrivateToken == "" {
+		return nil, resp, errors.New("session has no private token")
+	}
+
+	s.client.SetAuth(PrivateToken(session.PrivateToken))
+
+	return session, resp, err
+}
@@ -0,0 +1,167 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNewClient_noToken(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		for _, h := range []string{"PRIVATE-TOKEN", "JOB-TOKEN", "Authorization"} {
+			if _, ok := r.Header[http.CanonicalHeaderKey(h)]; ok {
+				t.Errorf("Request has a %s header, want none", h)
+			}
+		}
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	if _, _, err := client.Users.CurrentUser(); err != nil {
+		t.Errorf("Users.CurrentUser returned error: %v", err)
+	}
+}
+
+func TestSetAuth(t *testing.T) {
+	for _, v := range []struct {
+		auth   Authenticator
+		header string
+		want   string
+	}{
+		{PrivateToken("secret"), "PRIVATE-TOKEN", "secret"},
+		{JobToken("secret"), "JOB-TOKEN", "secret"},
+		{StaticOAuth("secret"), "Authorization", "Bearer secret"},
+	} {
+		mux, server, client := setup()
+		client.SetAuth(v.auth)
+
+		mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+			for _, h := range []string{"PRIVATE-TOKEN", "JOB-TOKEN", "Authorization"} {
+				want := ""
+				if h == v.header {
+					want = v.want
+				}
+				testHeader(t, r, h, want)
+			}
+			fmt.Fprint(w, `{"id":1}`)
+		})
+
+		if _, _, err := client.Users.CurrentUser(); err != nil {
+			t.Errorf("Users.CurrentUser returned error: %v", err)
+		}
+
+		teardown(server)
+	}
+}
+
+func TestOAuth_refresh(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	tokens := []string{"first", "second"}
+	client.SetAuth(OAuth(TokenSourceFunc(func() (string, error) {
+		token := tokens[0]
+		tokens = tokens[1:]
+		return token, nil
+	})))
+
+	var got []string
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	client.Users.CurrentUser()
+	client.Users.CurrentUser()
+
+	if len(got) != 2 || got[0] != "Bearer first" || got[1] != "Bearer second" {
+		t.Errorf("Requests were authenticated with %v, want the tokens of the source", got)
+	}
+}
+
+func TestOAuth_error(t *testing.T) {
+	_, server, client := setup()
+	defer teardown(server)
+
+	errExpired := errors.New("token expired")
+	client.SetAuth(OAuth(TokenSourceFunc(func() (string, error) {
+		return "", errExpired
+	})))
+
+	if _, _, err := client.Users.CurrentUser(); err != errExpired {
+		t.Errorf("Users.CurrentUser returned error %v, want %v", err, errExpired)
+	}
+}
+
+func TestSessionLogin(t *testing.T) {
+	mux, server, client := setupV3()
+	defer teardown(server)
+
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testJsonBody(t, r, values{
+			"login":    "jdoe",
+			"password": "secret",
+		})
+		fmt.Fprint(w, `{"id":1,"private_token":"token"}`)
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "PRIVATE-TOKEN", "token")
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	_, _, err := client.Session.Login(&GetSessionOptions{Login: "jdoe", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Session.Login returned error: %v", err)
+	}
+
+	if _, _, err := client.Users.CurrentUser(); err != nil {
+		t.Errorf("Users.CurrentUser returned error: %v", err)
+	}
+}
+
+func TestOAuth_refreshOnRetry(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+	tokens := []string{"expired", "fresh"}
+	client.SetAuth(OAuth(TokenSourceFunc(func() (string, error) {
+		token := tokens[0]
+		if len(tokens) > 1 {
+			tokens = tokens[1:]
+		}
+		return token, nil
+	})))
+
+	var got []string
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		if len(got) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	if _, _, err := client.Users.CurrentUser(); err != nil {
+		t.Fatalf("Users.CurrentUser returned error: %v", err)
+	}
+
+	if len(got) != 2 || got[0] != "Bearer expired" || got[1] != "Bearer fresh" {
+		t.Errorf("Attempts were authenticated with %v, want a fresh token for the retry", got)
+	}
+}
+
+func TestSessionLogin_v4(t *testing.T) {
+	_, server, client := setup()
+	defer teardown(server)
+
+	_, _, err := client.Session.Login(&GetSessionOptions{Login: "jdoe", Password: "secret"})
+	if err == nil {
+		t.Errorf("Session.Login returned no error with API v4")
+	}
+}
@@ -0,0 +1,138 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// DefaultBatchWorkers is the number of calls a batch makes concurrently,
+// unless specified otherwise.
+const DefaultBatchWorkers = 4
+
+// BatchOptions represents the available options when running a batch.
+type BatchOptions struct {
+	// The maximum number of calls in flight. Defaults to
+	// DefaultBatchWorkers.
+	Workers int
+
+	// Stop the batch on the first error. The calls in flight are cancelled,
+	// and the items which were not started yet fail with the error of the
+	// context.
+	StopOnError bool
+}
+
+// BatchFunc makes the calls for a single item of a batch, using the given
+// client. The client is bound to the context of the batch, so its calls are
+// cancelled when the batch stops.
+type BatchFunc[T, R any] func(c *Client, item T) (R, error)
+
+// BatchResult is the result of the calls made for a single item of a batch.
+type BatchResult[T, R any] struct {
+	Item  T
+	Value R
+	Err   error
+}
+
+// BatchResults holds the results of a batch, in the order of its items.
+type BatchResults[T, R any] []BatchResult[T, R]
+
+// Values returns the values of the items which succeeded.
+func (r BatchResults[T, R]) Values() []R {
+	var values []R
+	for _, res := range r {
+		if res.Err == nil {
+			values = append(values, res.Value)
+		}
+	}
+	return values
+}
+
+// Err returns the errors of the items which failed joined together, or nil
+// if all items succeeded.
+func (r BatchResults[T, R]) Err() error {
+	var errs []error
+	for _, res := range r {
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Batch calls f for every item, running at most opt.Workers calls at the same
+// time, and returns the results in the order of the items. The calls share
+// the rate limiter, retry policy and cache of c, so the rate limit of c is
+// respected whatever the number of workers. For example, to list the opened
+// merge requests of many projects:
+//
+//	results := gitlab.Batch(git, projects, nil,
+//		func(git *gitlab.Client, p *gitlab.Project) ([]*gitlab.MergeRequest, error) {
+//			opt := &gitlab.ListMergeRequestsOptions{State: "opened"}
+//			mrs, _, err := git.MergeRequests.ListMergeRequests(p, opt)
+//			return mrs, err
+//		})
+func Batch[T, R any](c *Client, items []T, opt *BatchOptions, f BatchFunc[T, R]) BatchResults[T, R] {
+	workers := DefaultBatchWorkers
+	stopOnError := false
+	if opt != nil {
+		if opt.Workers > 0 {
+			workers = opt.Workers
+		}
+		stopOnError = opt.StopOnError
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	bound := c.WithContext(ctx)
+
+	results := make(BatchResults[T, R], len(items))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				res := &results[i]
+				res.Item = items[i]
+				if err := ctx.Err(); err != nil {
+					res.Err = err
+					continue
+				}
+				res.Value, res.Err = f(bound, items[i])
+				if res.Err != nil && stopOnError {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,108 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	mux.HandleFunc("/projects/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		var id int
+		fmt.Sscanf(r.URL.Path, "/projects/%d", &id)
+		if id == 3 {
+			http.Error(w, `{"message":"404 Project Not Found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	})
+
+	ids := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	results := Batch(client, ids, &BatchOptions{Workers: 3}, func(c *Client, id int) (*Project, error) {
+		p, _, err := c.Projects.GetProject(ProjectID(id))
+		return p, err
+	})
+
+	if maxInFlight > 3 {
+		t.Errorf("Batch made %d concurrent calls, want at most 3", maxInFlight)
+	}
+	if len(results) != len(ids) {
+		t.Fatalf("Batch returned %d results, want %d", len(results), len(ids))
+	}
+	for i, res := range results {
+		if res.Item != ids[i] {
+			t.Errorf("Result %d is for item %d, want %d", i, res.Item, ids[i])
+		}
+		if res.Item == 3 {
+			if !errors.Is(res.Err, ErrNotFound) {
+				t.Errorf("Result for item 3 has error %v, want %v", res.Err, ErrNotFound)
+			}
+			continue
+		}
+		if res.Err != nil || *res.Value.ID != ids[i] {
+			t.Errorf("Result for item %d is %v, %v", ids[i], res.Value, res.Err)
+		}
+	}
+
+	if len(results.Values()) != 7 {
+		t.Errorf("Batch returned %d values, want 7", len(results.Values()))
+	}
+	if !errors.Is(results.Err(), ErrNotFound) {
+		t.Errorf("Batch returned error %v, want %v", results.Err(), ErrNotFound)
+	}
+}
+
+func TestBatch_stopOnError(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"403 Forbidden"}`, http.StatusForbidden)
+	})
+
+	ids := make([]int, 20)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	var mu sync.Mutex
+	calls := 0
+	results := Batch(client, ids, &BatchOptions{Workers: 2, StopOnError: true}, func(c *Client, id int) (*Project, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		p, _, err := c.Projects.GetProject(ProjectID(id))
+		return p, err
+	})
+
+	if calls > 3 {
+		t.Errorf("Batch made %d calls after an error, want at most 3", calls)
+	}
+	for _, res := range results {
+		if res.Err == nil {
+			t.Errorf("Result for item %d has no error", res.Item)
+		}
+	}
+}
@@ -18,7 +18,6 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
 )
 
 // BranchesService handles communication with the branch related methods
@@ -42,19 +41,30 @@ func (b Branch) String() string {
 	return Stringify(b)
 }
 
+// ListBranchesOptions represents the available ListBranches() options.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/branches.html#list-repository-branches
+type ListBranchesOptions struct {
+	ListOptions
+}
+
 // ListBranches gets a list of repository branches from a project, sorted by
 // name alphabetically.
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/branches.html#list-repository-branches
-func (s *BranchesService) ListBranches(pid interface{}) ([]*Branch, *Response, error) {
-	project, err := parseID(pid)
+func (s *BranchesService) ListBranches(
+	pid ProjectRef,
+	opt *ListBranchesOptions,
+	options ...OptionFunc) ([]*Branch, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/branches", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/branches", project)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -72,14 +82,17 @@ func (s *BranchesService) ListBranches(pid interface{}) ([]*Branch, *Response, e
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/branches.html#get-single-repository-branch
-func (s *BranchesService) GetBranch(pid interface{}, branch string) (*Branch, *Response, error) {
-	project, err := parseID(pid)
+func (s *BranchesService) GetBranch(
+	pid ProjectRef,
+	branch string,
+	options ...OptionFunc) (*Branch, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/branches/%s", url.QueryEscape(project), branch)
+	u := fmt.Sprintf("projects/%s/repository/branches/%s", project, branch)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -99,14 +112,17 @@ func (s *BranchesService) GetBranch(pid interface{}, branch string) (*Branch, *R
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/branches.html#protect-repository-branch
-func (s *BranchesService) ProtectBranch(pid interface{}, branch string) (*Branch, *Response, error) {
-	project, err := parseID(pid)
+func (s *BranchesService) ProtectBranch(
+	pid ProjectRef,
+	branch string,
+	options ...OptionFunc) (*Branch, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/branches/%s/protect", url.QueryEscape(project), branch)
+	u := fmt.Sprintf("projects/%s/repository/branches/%s/protect", project, branch)
 
-	req, err := s.client.NewRequest("PUT", u, nil)
+	req, err := s.client.NewRequest("PUT", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -127,15 +143,16 @@ func (s *BranchesService) ProtectBranch(pid interface{}, branch string) (*Branch
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/branches.html#unprotect-repository-branch
 func (s *BranchesService) UnprotectBranch(
-	pid interface{},
-	branch string) (*Branch, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	branch string,
+	options ...OptionFunc) (*Branch, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/branches/%s/unprotect", url.QueryEscape(project), branch)
+	u := fmt.Sprintf("projects/%s/repository/branches/%s/unprotect", project, branch)
 
-	req, err := s.client.NewRequest("PUT", u, nil)
+	req, err := s.client.NewRequest("PUT", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -155,23 +172,31 @@ func (s *BranchesService) UnprotectBranch(
 // http://doc.gitlab.com/ce/api/branches.html#create-repository-branch
 type CreateBranchOptions struct {
 	BranchName string `url:"branch_name,omitempty" json:"branch_name,omitempty"`
+	Branch     string `url:"branch,omitempty" json:"branch,omitempty"`
 	Ref        string `url:"ref,omitempty" json:"ref,omitempty"`
 }
 
-// CreateBranch creates branch from commit SHA or existing branch.
+// CreateBranch creates branch from commit SHA or existing branch. API v4
+// renamed branch_name to branch, so either BranchName or Branch can be used.
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/branches.html#create-repository-branch
 func (s *BranchesService) CreateBranch(
-	pid interface{},
-	opt *CreateBranchOptions) (*Branch, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CreateBranchOptions,
+	options ...OptionFunc) (*Branch, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/branches", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/branches", project)
+	if s.client.apiVersion != APIVersion3 && opt != nil && opt.Branch == "" {
+		o := *opt
+		o.Branch, o.BranchName = o.BranchName, ""
+		opt = &o
+	}
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -189,14 +214,17 @@ func (s *BranchesService) CreateBranch(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/branches.html#delete-repository-branch
-func (s *BranchesService) DeleteBranch(pid interface{}, branch string) (*Response, error) {
-	project, err := parseID(pid)
+func (s *BranchesService) DeleteBranch(
+	pid ProjectRef,
+	branch string,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/branches/%s", url.QueryEscape(project), branch)
+	u := fmt.Sprintf("projects/%s/repository/branches/%s", project, branch)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
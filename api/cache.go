@@ -0,0 +1,314 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// A Cache stores the responses of GET requests, so they can be revalidated
+// using conditional requests. Keys are opaque strings and values are the
+// serialized responses. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the response stored under key, if any.
+	Get(key string) ([]byte, bool)
+
+	// Set stores a response under key.
+	Set(key string, response []byte)
+
+	// Delete removes the response stored under key.
+	Delete(key string)
+}
+
+// CacheStatus tells how the cache of a Client was used for a response.
+type CacheStatus int
+
+// List of available cache statuses
+const (
+	// The response is not cacheable, or the Client has no cache.
+	CacheNone CacheStatus = iota
+
+	// The response was not in the cache, or it was stale, and was fetched
+	// from GitLab.
+	CacheMiss
+
+	// GitLab reported the cached response to be still valid (304 Not
+	// Modified) and the response was served from the cache.
+	CacheHit
+)
+
+func (s CacheStatus) String() string {
+	switch s {
+	case CacheMiss:
+		return "miss"
+	case CacheHit:
+		return "hit"
+	}
+	return "none"
+}
+
+// SetCache sets the cache used for conditional requests. When a cache is set,
+// the responses of GET requests carrying an ETag or Last-Modified header are
+// stored, and revalidated with If-None-Match and If-Modified-Since headers the
+// next time the same resource is requested. A nil cache disables caching,
+// which is the default.
+//
+// Responses are keyed by URL and by the credentials and sudo user of the
+// request. When the HTTP client of the Client authenticates requests itself,
+// the Client cannot tell users apart, so use a separate cache per user (see
+// PrefixCache).
+func (c *Client) SetCache(cache Cache) {
+	c.cache = cache
+}
+
+// cacheKeyHeaders are the request headers which identify the user a response
+// was returned for.
+var cacheKeyHeaders = []string{"Authorization", "PRIVATE-TOKEN", "JOB-TOKEN", "SUDO"}
+
+// cacheKey returns the key of the response to req.
+func cacheKey(req *http.Request) string {
+	h := sha256.New()
+	io.WriteString(h, req.URL.String())
+	for _, k := range cacheKeyHeaders {
+		io.WriteString(h, "\n"+k+": "+req.Header.Get(k))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sendCached sends req, using the cache of c to make a conditional request
+// when possible.
+func (c *Client) sendCached(req *http.Request) (*http.Response, CacheStatus, error) {
+	if c.cache == nil || req.Method != "GET" || req.Header.Get("Range") != "" {
+		resp, err := c.send(req)
+		return resp, CacheNone, err
+	}
+
+	key := cacheKey(req)
+	cached := loadResponse(c.cache, key, req)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, CacheNone, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		// Drain the body so the connection can be reused.
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		// The headers of the 304 response update the stored ones.
+		for k, v := range resp.Header {
+			cached.Header[k] = v
+		}
+		return cached, CacheHit, nil
+	}
+
+	if resp.StatusCode == http.StatusOK &&
+		(resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "") {
+		// DumpResponse reads the body, and replaces it with a copy.
+		if data, err := httputil.DumpResponse(resp, true); err == nil {
+			c.cache.Set(key, data)
+		}
+	}
+
+	return resp, CacheMiss, nil
+}
+
+// loadResponse returns the response to req stored under key, or nil if there
+// is none or it cannot be read.
+func loadResponse(cache Cache, key string, req *http.Request) *http.Response {
+	data, ok := cache.Get(key)
+	if !ok {
+		return nil
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		cache.Delete(key)
+		return nil
+	}
+
+	return resp
+}
+
+// MemoryCache is a Cache which keeps responses in memory. When it holds its
+// maximum number of responses, the least recently used one is evicted.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // of *memoryEntry, most recently used first
+	entries    map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key      string
+	response []byte
+}
+
+// NewMemoryCache returns a new, empty MemoryCache holding at most maxEntries
+// responses. Zero or less means no limit, which is only safe for short-lived
+// programs.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements the Cache interface.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*memoryEntry).response, true
+}
+
+// Set implements the Cache interface.
+func (c *MemoryCache) Set(key string, response []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*memoryEntry).response = response
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key, response})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Delete implements the Cache interface.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of responses in the cache.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache is a Cache which stores responses as files in a directory.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a DiskCache storing responses in dir, which is created
+// if it does not exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// path returns the path of the file storing the response of key.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements the Cache interface.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	response, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return response, true
+}
+
+// Set implements the Cache interface. Responses are written to a temporary
+// file first, so concurrent readers never see a partial response.
+func (c *DiskCache) Set(key string, response []byte) {
+	f, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(response)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// Delete implements the Cache interface.
+func (c *DiskCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
+
+// PrefixCache returns a Cache which stores its responses in cache, prefixing
+// their keys with prefix. It allows several users to share a single cache.
+func PrefixCache(prefix string, cache Cache) Cache {
+	return &prefixCache{prefix: prefix, cache: cache}
+}
+
+type prefixCache struct {
+	prefix string
+	cache  Cache
+}
+
+func (c *prefixCache) Get(key string) ([]byte, bool) {
+	return c.cache.Get(c.prefix + key)
+}
+
+func (c *prefixCache) Set(key string, response []byte) {
+	c.cache.Set(c.prefix+key, response)
+}
+
+func (c *prefixCache) Delete(key string) {
+	c.cache.Delete(c.prefix + key)
+}
@@ -0,0 +1,142 @@
+package gitlab
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// cachedUser registers a handler on mux which serves the current user with an
+// ETag, honouring If-None-Match, and counts the responses it sends in full.
+func cachedUser(t *testing.T, mux *http.ServeMux) *int {
+	var full int
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		etag := `"v1-` + r.Header.Get("PRIVATE-TOKEN") + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		fmt.Fprintf(w, `{"id":1,"username":"%s"}`, r.Header.Get("PRIVATE-TOKEN"))
+	})
+
+	return &full
+}
+
+func testCache(t *testing.T, cache Cache) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	full := cachedUser(t, mux)
+	client.SetCache(cache)
+	client.SetAuth(PrivateToken("jdoe"))
+
+	for i, want := range []CacheStatus{CacheMiss, CacheHit, CacheHit} {
+		user, resp, err := client.Users.CurrentUser()
+		if err != nil {
+			t.Fatalf("Users.CurrentUser returned error: %v", err)
+		}
+		if user.Username != "jdoe" {
+			t.Errorf("Users.CurrentUser returned %+v, want jdoe", user)
+		}
+		if resp.StatusCode != http.StatusOK || resp.Cache != want {
+			t.Errorf("Request %d: got status %d and cache %s, want 200 and %s",
+				i, resp.StatusCode, resp.Cache, want)
+		}
+	}
+
+	// Another user must not get the cached response of the first one.
+	client.SetAuth(PrivateToken("admin"))
+	user, resp, err := client.Users.CurrentUser()
+	if err != nil {
+		t.Fatalf("Users.CurrentUser returned error: %v", err)
+	}
+	if user.Username != "admin" || resp.Cache != CacheMiss {
+		t.Errorf("Users.CurrentUser returned %+v from cache %s, want admin from GitLab", user, resp.Cache)
+	}
+
+	if *full != 2 {
+		t.Errorf("GitLab sent %d full responses, want 2", *full)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	testCache(t, NewMemoryCache(0))
+}
+
+func TestMemoryCache_evict(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", []byte("a"))
+	c.Set("b", []byte("b"))
+	c.Get("a") // b is now the least recently used
+	c.Set("c", []byte("c"))
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("MemoryCache kept b, want it evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("MemoryCache evicted %s", key)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("MemoryCache holds %d responses, want 2", c.Len())
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gitlab-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("NewDiskCache returned error: %v", err)
+	}
+	testCache(t, cache)
+}
+
+func TestCache_disabled(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	full := cachedUser(t, mux)
+
+	for i := 0; i < 2; i++ {
+		_, resp, err := client.Users.CurrentUser()
+		if err != nil {
+			t.Fatalf("Users.CurrentUser returned error: %v", err)
+		}
+		if resp.Cache != CacheNone {
+			t.Errorf("Response.Cache is %s, want %s", resp.Cache, CacheNone)
+		}
+	}
+
+	if *full != 2 {
+		t.Errorf("GitLab sent %d full responses, want 2", *full)
+	}
+}
+
+func TestPrefixCache(t *testing.T) {
+	shared := NewMemoryCache(0)
+	a, b := PrefixCache("a", shared), PrefixCache("b", shared)
+
+	a.Set("key", []byte("a"))
+	if _, ok := b.Get("key"); ok {
+		t.Errorf("PrefixCache b returned the response of a")
+	}
+	if v, ok := a.Get("key"); !ok || string(v) != "a" {
+		t.Errorf("PrefixCache a returned %q, %t, want %q", v, ok, "a")
+	}
+
+	a.Delete("key")
+	if _, ok := shared.Get("akey"); ok {
+		t.Errorf("PrefixCache did not delete the response")
+	}
+}
@@ -18,8 +18,6 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
-	"time"
 )
 
 // CommitsService handles communication with the commit related methods
@@ -34,16 +32,16 @@ type CommitsService struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/commits.html
 type Commit struct {
-	ID            string    `json:"id"`
-	ShortID       string    `json:"short_id"`
-	Title         string    `json:"title"`
-	AuthorName    string    `json:"author_name"`
-	AuthorEmail   string    `json:"author_email"`
-	AuthoredDate  time.Time `json:"authored_date"`
-	CommittedDate time.Time `json:"committed_date"`
-	CreatedAt     time.Time `json:"created_at"`
-	Message       string    `json:"message"`
-	ParentsIds    []string  `json:"parents_ids"`
+	ID            string   `json:"id"`
+	ShortID       string   `json:"short_id"`
+	Title         string   `json:"title"`
+	AuthorName    string   `json:"author_name"`
+	AuthorEmail   string   `json:"author_email"`
+	AuthoredDate  Time     `json:"authored_date"`
+	CommittedDate Time     `json:"committed_date"`
+	CreatedAt     Time     `json:"created_at"`
+	Message       string   `json:"message"`
+	ParentsIds    []string `json:"parents_ids"`
 }
 
 func (c Commit) String() string {
@@ -62,15 +60,16 @@ type ListCommitsOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/commits.html#list-commits
 func (s *CommitsService) ListCommits(
-	pid interface{},
-	opt *ListCommitsOptions) ([]*Commit, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ListCommitsOptions,
+	options ...OptionFunc) ([]*Commit, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/commits", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/commits", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -89,15 +88,16 @@ func (s *CommitsService) ListCommits(
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/commits.html#get-a-single-commit
 func (s *CommitsService) GetCommit(
-	pid interface{},
-	sha string) (*Commit, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	sha string,
+	options ...OptionFunc) (*Commit, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/commits/%s", url.QueryEscape(project), sha)
+	u := fmt.Sprintf("projects/%s/repository/commits/%s", project, sha)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -134,15 +134,16 @@ func (d Diff) String() string {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/commits.html#get-the-diff-of-a-commit
 func (s *CommitsService) GetCommitDiff(
-	pid interface{},
-	sha string) ([]*Diff, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	sha string,
+	options ...OptionFunc) ([]*Diff, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/commits/%s/diff", url.QueryEscape(project), sha)
+	u := fmt.Sprintf("projects/%s/repository/commits/%s/diff", project, sha)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -168,33 +169,44 @@ type CommitComment struct {
 }
 
 type Author struct {
-	ID        int       `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	State     string    `json:"state"`
-	Blocked   bool      `json:"blocked"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Blocked   bool   `json:"blocked"`
+	CreatedAt Time   `json:"created_at"`
 }
 
 func (c CommitComment) String() string {
 	return Stringify(c)
 }
 
+// GetCommitCommentsOptions represents the available GetCommitComments()
+// options.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/commits.html#get-the-comments-of-a-commit
+type GetCommitCommentsOptions struct {
+	ListOptions
+}
+
 // GetCommitComments gets the comments of a commit in a project.
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/commits.html#get-the-comments-of-a-commit
 func (s *CommitsService) GetCommitComments(
-	pid interface{},
-	sha string) ([]*CommitComment, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	sha string,
+	opt *GetCommitCommentsOptions,
+	options ...OptionFunc) ([]*CommitComment, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/commits/%s/comments", url.QueryEscape(project), sha)
+	u := fmt.Sprintf("projects/%s/repository/commits/%s/comments", project, sha)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -227,16 +239,17 @@ type PostCommitCommentOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/commits.html#post-comment-to-commit
 func (s *CommitsService) PostCommitComment(
-	pid interface{},
+	pid ProjectRef,
 	sha string,
-	opt *PostCommitCommentOptions) (*CommitComment, *Response, error) {
-	project, err := parseID(pid)
+	opt *PostCommitCommentOptions,
+	options ...OptionFunc) (*CommitComment, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/commits/%s/comments", url.QueryEscape(project), sha)
+	u := fmt.Sprintf("projects/%s/repository/commits/%s/comments", project, sha)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -264,33 +277,34 @@ type GetCommitStatusesOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/commits.html#get-the-status-of-a-commit
 type CommitStatus struct {
-	ID          int       `json:"id"`
-	SHA         string    `json:"sha"`
-	Ref         string    `json:"ref"`
-	Status      string    `json:"status"`
-	Name        string    `json:"name"`
-	TargetUrl   string    `json:"target_url"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	StartedAt   time.Time `json:"started_at"`
-	FinishedAt  time.Time `json:"finished_at"`
-	Author      Author    `json:"author"`
+	ID          int    `json:"id"`
+	SHA         string `json:"sha"`
+	Ref         string `json:"ref"`
+	Status      string `json:"status"`
+	Name        string `json:"name"`
+	TargetUrl   string `json:"target_url"`
+	Description string `json:"description"`
+	CreatedAt   Time   `json:"created_at"`
+	StartedAt   Time   `json:"started_at"`
+	FinishedAt  Time   `json:"finished_at"`
+	Author      Author `json:"author"`
 }
 
 // GetCommitStatuses gets the statuses of a commit in a project.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/commits.html#get-the-status-of-a-commit
 func (s *CommitsService) GetCommitStatuses(
-	pid interface{},
+	pid ProjectRef,
 	sha string,
-	opt *GetCommitStatusesOptions) ([]*CommitStatus, *Response, error) {
-	project, err := parseID(pid)
+	opt *GetCommitStatusesOptions,
+	options ...OptionFunc) ([]*CommitStatus, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/commits/%s/statuses", url.QueryEscape(project), sha)
+	u := fmt.Sprintf("projects/%s/repository/commits/%s/statuses", project, sha)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -316,30 +330,37 @@ type SetCommitStatusOptions struct {
 	Description string     `url:"description,omitempty" json:"description,omitempty"`
 }
 
+// BuildState represents the state of a commit status, a build or a pipeline.
 type BuildState string
 
+// These constants represent all valid build states. Commit statuses can only
+// be set to pending, running, success, failed or canceled.
 const (
+	Created  BuildState = "created"
 	Pending  BuildState = "pending"
 	Running  BuildState = "running"
 	Success  BuildState = "success"
 	Failed   BuildState = "failed"
 	Canceled BuildState = "canceled"
+	Skipped  BuildState = "skipped"
+	Manual   BuildState = "manual"
 )
 
 // SetCommitStatus sets the status of a commit in a project.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/commits.html#post-the-status-to-commit
 func (s *CommitsService) SetCommitStatus(
-	pid interface{},
+	pid ProjectRef,
 	sha string,
-	opt *SetCommitStatusOptions) (*CommitStatus, *Response, error) {
-	project, err := parseID(pid)
+	opt *SetCommitStatusOptions,
+	options ...OptionFunc) (*CommitStatus, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/statuses/%s", url.QueryEscape(project), sha)
+	u := fmt.Sprintf("projects/%s/statuses/%s", project, sha)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
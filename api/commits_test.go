@@ -23,7 +23,7 @@ func TestGetCommitStatuses(t *testing.T) {
 	})
 
 	opt := &GetCommitStatusesOptions{"master", "test", "ci/jenkins", true}
-	statuses, _, err := client.Commits.GetCommitStatuses("1", "b0b3a907f41409829b307a28b82fdbd552ee5a27", opt)
+	statuses, _, err := client.Commits.GetCommitStatuses(ProjectID(1), "b0b3a907f41409829b307a28b82fdbd552ee5a27", opt)
 
 	if err != nil {
 		t.Errorf("Commits.GetCommitStatuses returned error: %v", err)
@@ -52,7 +52,7 @@ func TestSetCommitStatus(t *testing.T) {
 	})
 
 	opt := &SetCommitStatusOptions{Running, "master", "ci/jenkins", "", "http://abc", "build"}
-	status, _, err := client.Commits.SetCommitStatus("1", "b0b3a907f41409829b307a28b82fdbd552ee5a27", opt)
+	status, _, err := client.Commits.SetCommitStatus(ProjectID(1), "b0b3a907f41409829b307a28b82fdbd552ee5a27", opt)
 
 	if err != nil {
 		t.Errorf("Commits.SetCommitStatus returned error: %v", err)
@@ -18,8 +18,6 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
-	"time"
 )
 
 // DeployKeysService handles communication with the keys related methods
@@ -32,10 +30,10 @@ type DeployKeysService struct {
 
 // DeployKey represents a GitLab deploy key.
 type DeployKey struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Key       string    `json:"key"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Key       string `json:"key"`
+	CreatedAt Time   `json:"created_at"`
 }
 
 func (k DeployKey) String() string {
@@ -46,14 +44,16 @@ func (k DeployKey) String() string {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/deploy_keys.html#list-deploy-keys
-func (s *DeployKeysService) ListDeployKeys(pid interface{}) ([]*DeployKey, *Response, error) {
-	project, err := parseID(pid)
+func (s *DeployKeysService) ListDeployKeys(
+	pid ProjectRef,
+	options ...OptionFunc) ([]*DeployKey, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/keys", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/keys", project)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -72,15 +72,16 @@ func (s *DeployKeysService) ListDeployKeys(pid interface{}) ([]*DeployKey, *Resp
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/deploy_keys.html#single-deploy-key
 func (s *DeployKeysService) GetDeployKey(
-	pid interface{},
-	deployKey int) (*DeployKey, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	deployKey int,
+	options ...OptionFunc) (*DeployKey, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/keys/%d", url.QueryEscape(project), deployKey)
+	u := fmt.Sprintf("projects/%s/keys/%d", project, deployKey)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -110,15 +111,16 @@ type AddDeployKeyOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/deploy_keys.html#add-deploy-key
 func (s *DeployKeysService) AddDeployKey(
-	pid interface{},
-	opt *AddDeployKeyOptions) (*DeployKey, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *AddDeployKeyOptions,
+	options ...OptionFunc) (*DeployKey, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/keys", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/keys", project)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -136,14 +138,17 @@ func (s *DeployKeysService) AddDeployKey(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/deploy_keys.html#delete-deploy-key
-func (s *DeployKeysService) DeleteDeployKey(pid interface{}, deployKey int) (*Response, error) {
-	project, err := parseID(pid)
+func (s *DeployKeysService) DeleteDeployKey(
+	pid ProjectRef,
+	deployKey int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/keys/%d", url.QueryEscape(project), deployKey)
+	u := fmt.Sprintf("projects/%s/keys/%d", project, deployKey)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,118 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"fmt"
+)
+
+// DeploymentsService handles communication with the deployment related
+// methods of the GitLab API.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/deployments.html
+type DeploymentsService struct {
+	client *Client
+}
+
+// Deployment represents a deployment of a commit to an environment. The
+// Deployable is the job which deployed it, along with its commit.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/deployments.html
+type Deployment struct {
+	ID          int          `json:"id"`
+	IID         int          `json:"iid"`
+	Ref         string       `json:"ref"`
+	SHA         string       `json:"sha"`
+	Status      BuildState   `json:"status"`
+	CreatedAt   *Time        `json:"created_at"`
+	UpdatedAt   *Time        `json:"updated_at"`
+	User        *User        `json:"user"`
+	Environment *Environment `json:"environment"`
+	Deployable  *Job         `json:"deployable"`
+}
+
+func (d Deployment) String() string {
+	return Stringify(d)
+}
+
+// ListProjectDeploymentsOptions represents the available
+// ListProjectDeployments() options. Deployments can be ordered by "id",
+// "iid", "created_at" or "ref".
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/deployments.html#list-project-deployments
+type ListProjectDeploymentsOptions struct {
+	ListOptions
+	OrderBy string `url:"order_by,omitempty" json:"order_by,omitempty"`
+	Sort    string `url:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// ListProjectDeployments gets a list of the deployments of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/deployments.html#list-project-deployments
+func (s *DeploymentsService) ListProjectDeployments(
+	pid ProjectRef,
+	opt *ListProjectDeploymentsOptions,
+	options ...OptionFunc) ([]*Deployment, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/deployments", project)
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var d []*Deployment
+	resp, err := s.client.Do(req, &d)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return d, resp, err
+}
+
+// GetProjectDeployment gets a single deployment of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/deployments.html#get-a-specific-deployment
+func (s *DeploymentsService) GetProjectDeployment(
+	pid ProjectRef,
+	deployment int,
+	options ...OptionFunc) (*Deployment, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/deployments/%d", project, deployment)
+
+	req, err := s.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := new(Deployment)
+	resp, err := s.client.Do(req, d)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return d, resp, err
+}
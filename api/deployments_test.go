@@ -0,0 +1,50 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListProjectDeployments(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/deployments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"order_by": "created_at",
+			"sort":     "desc",
+		})
+		fmt.Fprint(w, `[{"id":42,"iid":2,"ref":"master","sha":"a91957a8","environment":{"id":9,"name":"production"}}]`)
+	})
+
+	opt := &ListProjectDeploymentsOptions{OrderBy: "created_at", Sort: "desc"}
+	deployments, _, err := client.Deployments.ListProjectDeployments(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("Deployments.ListProjectDeployments returned error: %v", err)
+	}
+
+	if len(deployments) != 1 || deployments[0].Environment.Name != "production" {
+		t.Errorf("Deployments.ListProjectDeployments returned %+v", deployments)
+	}
+}
+
+func TestGetProjectDeployment(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/deployments/42", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":42,"sha":"a91957a8","user":{"username":"root"},"deployable":{"id":7,"name":"deploy","status":"success","commit":{"id":"a91957a8","message":"Release"}}}`)
+	})
+
+	d, _, err := client.Deployments.GetProjectDeployment(ProjectID(1), 42)
+	if err != nil {
+		t.Fatalf("Deployments.GetProjectDeployment returned error: %v", err)
+	}
+
+	if d.User.Username != "root" || d.Deployable.Name != "deploy" || d.Deployable.Commit.Message != "Release" {
+		t.Errorf("Deployments.GetProjectDeployment returned %+v", d)
+	}
+}
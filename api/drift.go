@@ -0,0 +1,291 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UnknownFieldsHandler is told about the fields of API responses which are
+// not mapped by the types they are decoded into, see SetUnknownFieldsHandler.
+type UnknownFieldsHandler interface {
+	// UnknownFields is called with the method and normalized endpoint of
+	// the request, the name of the type the response was decoded into, and
+	// the paths of the unknown fields, e.g. "author.avatar_url".
+	UnknownFields(method, endpoint, typ string, fields []string)
+}
+
+// The UnknownFieldsFunc type is an adapter to allow the use of ordinary
+// functions as an UnknownFieldsHandler.
+type UnknownFieldsFunc func(method, endpoint, typ string, fields []string)
+
+// UnknownFields calls f(method, endpoint, typ, fields).
+func (f UnknownFieldsFunc) UnknownFields(method, endpoint, typ string, fields []string) {
+	f(method, endpoint, typ, fields)
+}
+
+// SetUnknownFieldsHandler enables the detection of unknown fields: the JSON
+// responses decoded by Do are compared to the types they are decoded into,
+// and the fields which are dropped are reported to h. This tells which fields
+// GitLab added to its API, but costs a second decoding of every response. nil
+// disables the detection.
+func (c *Client) SetUnknownFieldsHandler(h UnknownFieldsHandler) {
+	c.unknownFields = h
+}
+
+// decodeStrict decodes the JSON response resp to req into v, and reports its
+// unknown fields to the handler of c.
+func (c *Client) decodeStrict(req *http.Request, resp *http.Response, v interface{}) error {
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(v); err != nil {
+		return err
+	}
+
+	fields, err := UnknownFields(data, v)
+	if err != nil || len(fields) == 0 {
+		return nil
+	}
+	c.unknownFields.UnknownFields(req.Method, Endpoint(req), typeName(reflect.TypeOf(v)), fields)
+
+	return nil
+}
+
+// UnknownFields returns the sorted paths of the fields of the JSON payload
+// data which are not mapped by v, the value data is decoded into (e.g.
+// *MergeRequest or *[]*Project). Nested fields are separated by dots, e.g.
+// "author.avatar_url". The contents of types with their own UnmarshalJSON
+// method, maps of interface{} and interface{} values are not checked.
+func UnknownFields(data []byte, v interface{}) ([]string, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	unknownFields(raw, reflect.TypeOf(v), "", found)
+
+	fields := make([]string, 0, len(found))
+	for f := range found {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// unknownFields adds the paths of the fields of raw which t does not map to
+// found, prefixed by path.
+func unknownFields(raw interface{}, t reflect.Type, path string, found map[string]bool) {
+	if t == nil || raw == nil {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshaler) || reflect.PtrTo(t).Implements(textUnmarshaler) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, value := range obj {
+			name := joinPath(path, key)
+			ft, ok := fields[strings.ToLower(key)]
+			if !ok {
+				found[name] = true
+				continue
+			}
+			unknownFields(value, ft, name, found)
+		}
+
+	case reflect.Slice, reflect.Array:
+		list, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for _, value := range list {
+			unknownFields(value, t.Elem(), path, found)
+		}
+
+	case reflect.Map:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, value := range obj {
+			unknownFields(value, t.Elem(), joinPath(path, "*"), found)
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// structFields caches the JSON fields of struct types, see jsonFields.
+var structFields sync.Map
+
+// jsonFields returns the types of the fields of the struct type t, by their
+// lowercased JSON name, as encoding/json matches names case-insensitively.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := structFields.Load(t); ok {
+		return fields.(map[string]reflect.Type)
+	}
+
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for n, t := range jsonFields(ft) {
+				if _, ok := fields[n]; !ok {
+					fields[n] = t
+				}
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+
+	structFields.Store(t, fields)
+	return fields
+}
+
+// typeName returns the name of the type of the elements held by t, e.g.
+// "Project" for *[]*Project.
+func typeName(t reflect.Type) string {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+			continue
+		}
+		if t.Name() != "" {
+			return t.Name()
+		}
+		return t.String()
+	}
+}
+
+// UnknownField is a field of API responses which is not mapped by the type
+// the responses are decoded into.
+type UnknownField struct {
+	Type     string `json:"type"`
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	Field    string `json:"field"`
+	Count    int    `json:"count"`
+}
+
+// DriftReport is an UnknownFieldsHandler collecting the unknown fields per
+// type and endpoint. It implements the expvar.Var interface, so it can be
+// published along with the other variables of a program.
+type DriftReport struct {
+	mu     sync.Mutex
+	fields map[UnknownField]int
+}
+
+// NewDriftReport returns an empty DriftReport.
+func NewDriftReport() *DriftReport {
+	return &DriftReport{fields: make(map[UnknownField]int)}
+}
+
+// UnknownFields implements the UnknownFieldsHandler interface.
+func (r *DriftReport) UnknownFields(method, endpoint, typ string, fields []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range fields {
+		r.fields[UnknownField{Type: typ, Method: method, Endpoint: endpoint, Field: f}]++
+	}
+}
+
+// Fields returns the unknown fields reported so far, along with the number of
+// responses holding them, sorted by type, field and endpoint.
+func (r *DriftReport) Fields() []UnknownField {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fields := make([]UnknownField, 0, len(r.fields))
+	for f, n := range r.fields {
+		f.Count = n
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		return a.Method < b.Method
+	})
+
+	return fields
+}
+
+// String returns the unknown fields as JSON.
+func (r *DriftReport) String() string {
+	data, err := json.Marshal(r.Fields())
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
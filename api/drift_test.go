@@ -0,0 +1,68 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestUnknownFields(t *testing.T) {
+	type base struct {
+		ID int `json:"id"`
+	}
+	type item struct {
+		base
+		Name    string                 `json:"name"`
+		Ignored string                 `json:"-"`
+		Extra   map[string]interface{} `json:"extra"`
+		Owner   *struct {
+			Username string `json:"username"`
+		} `json:"owner"`
+	}
+
+	data := []byte(`[
+		{"id":1,"NAME":"a","extra":{"x":1},"owner":{"username":"root","web_url":"u"},"ignored":"i"},
+		{"id":2,"name":"b","owner":null,"star_count":3}
+	]`)
+
+	fields, err := UnknownFields(data, &[]*item{})
+	if err != nil {
+		t.Fatalf("UnknownFields returned error: %v", err)
+	}
+
+	want := []string{"ignored", "owner.web_url", "star_count"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("UnknownFields returned %v, want %v", fields, want)
+	}
+}
+
+func TestSetUnknownFieldsHandler(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id":1,"merge_status":"can_be_merged","author":{"id":1,"web_url":"u"},"created_at":"2016-01-04T15:31:51.081Z"}]`)
+	})
+
+	report := NewDriftReport()
+	client.SetUnknownFieldsHandler(report)
+
+	for i := 0; i < 2; i++ {
+		mrs, _, err := client.MergeRequests.ListMergeRequests(ProjectID(1), nil)
+		if err != nil {
+			t.Fatalf("MergeRequests.ListMergeRequests returned error: %v", err)
+		}
+		if len(mrs) != 1 || mrs[0].ID != 1 || mrs[0].CreatedAt.IsZero() {
+			t.Fatalf("MergeRequests.ListMergeRequests returned %+v", mrs)
+		}
+	}
+
+	want := []UnknownField{
+		{Type: "MergeRequest", Method: "GET", Endpoint: "projects/:id/merge_requests", Field: "author.web_url", Count: 2},
+		{Type: "MergeRequest", Method: "GET", Endpoint: "projects/:id/merge_requests", Field: "merge_status", Count: 2},
+	}
+	if got := report.Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DriftReport.Fields returned %+v, want %+v", got, want)
+	}
+}
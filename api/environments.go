@@ -0,0 +1,223 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"fmt"
+)
+
+// EnvironmentsService handles communication with the environment related
+// methods of the GitLab API.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/environments.html
+type EnvironmentsService struct {
+	client *Client
+}
+
+// Environment represents a GitLab environment, e.g. staging or production.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/environments.html
+type Environment struct {
+	ID             int         `json:"id"`
+	Name           string      `json:"name"`
+	Slug           string      `json:"slug"`
+	State          string      `json:"state"`
+	ExternalURL    string      `json:"external_url"`
+	LastDeployment *Deployment `json:"last_deployment"`
+}
+
+func (e Environment) String() string {
+	return Stringify(e)
+}
+
+// ListEnvironmentsOptions represents the available ListEnvironments()
+// options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#list-environments
+type ListEnvironmentsOptions struct {
+	ListOptions
+	Name   string `url:"name,omitempty" json:"name,omitempty"`
+	Search string `url:"search,omitempty" json:"search,omitempty"`
+}
+
+// ListEnvironments gets a list of the environments of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#list-environments
+func (s *EnvironmentsService) ListEnvironments(
+	pid ProjectRef,
+	opt *ListEnvironmentsOptions,
+	options ...OptionFunc) ([]*Environment, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/environments", project)
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var e []*Environment
+	resp, err := s.client.Do(req, &e)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return e, resp, err
+}
+
+// GetEnvironment gets a single environment of a project, along with its last
+// deployment.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#get-a-specific-environment
+func (s *EnvironmentsService) GetEnvironment(
+	pid ProjectRef,
+	environment int,
+	options ...OptionFunc) (*Environment, *Response, error) {
+	return s.environmentRequest("GET", pid, environment, "", nil, options)
+}
+
+// CreateEnvironmentOptions represents the available CreateEnvironment()
+// options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#create-a-new-environment
+type CreateEnvironmentOptions struct {
+	Name        string `url:"name" json:"name"`
+	ExternalURL string `url:"external_url,omitempty" json:"external_url,omitempty"`
+}
+
+// CreateEnvironment creates a new environment.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#create-a-new-environment
+func (s *EnvironmentsService) CreateEnvironment(
+	pid ProjectRef,
+	opt *CreateEnvironmentOptions,
+	options ...OptionFunc) (*Environment, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/environments", project)
+
+	req, err := s.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e := new(Environment)
+	resp, err := s.client.Do(req, e)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return e, resp, err
+}
+
+// EditEnvironmentOptions represents the available EditEnvironment() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#edit-an-existing-environment
+type EditEnvironmentOptions struct {
+	Name        string `url:"name,omitempty" json:"name,omitempty"`
+	ExternalURL string `url:"external_url,omitempty" json:"external_url,omitempty"`
+}
+
+// EditEnvironment updates an existing environment.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#edit-an-existing-environment
+func (s *EnvironmentsService) EditEnvironment(
+	pid ProjectRef,
+	environment int,
+	opt *EditEnvironmentOptions,
+	options ...OptionFunc) (*Environment, *Response, error) {
+	return s.environmentRequest("PUT", pid, environment, "", opt, options)
+}
+
+// StopEnvironment stops an environment, running its stop action if it has
+// one.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#stop-an-environment
+func (s *EnvironmentsService) StopEnvironment(
+	pid ProjectRef,
+	environment int,
+	options ...OptionFunc) (*Environment, *Response, error) {
+	return s.environmentRequest("POST", pid, environment, "/stop", nil, options)
+}
+
+// environmentRequest sends a request to the given path of an environment, and
+// returns the resulting environment.
+func (s *EnvironmentsService) environmentRequest(
+	method string,
+	pid ProjectRef,
+	environment int,
+	path string,
+	opt interface{},
+	options []OptionFunc) (*Environment, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/environments/%d%s", project, environment, path)
+
+	req, err := s.client.NewRequest(method, u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e := new(Environment)
+	resp, err := s.client.Do(req, e)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return e, resp, err
+}
+
+// DeleteEnvironment deletes an environment.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/environments.html#delete-an-environment
+func (s *EnvironmentsService) DeleteEnvironment(
+	pid ProjectRef,
+	environment int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("projects/%s/environments/%d", project, environment)
+
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
@@ -0,0 +1,101 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListEnvironments(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/environments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"name": "production"})
+		fmt.Fprint(w, `[{"id":1,"name":"production","slug":"production","external_url":"https://example.com","state":"available"}]`)
+	})
+
+	envs, _, err := client.Environments.ListEnvironments(ProjectID(1), &ListEnvironmentsOptions{Name: "production"})
+	if err != nil {
+		t.Fatalf("Environments.ListEnvironments returned error: %v", err)
+	}
+
+	if len(envs) != 1 || envs[0].ExternalURL != "https://example.com" {
+		t.Errorf("Environments.ListEnvironments returned %+v", envs)
+	}
+}
+
+func TestGetEnvironment(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/environments/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":1,"name":"production","last_deployment":{"id":100,"sha":"99d03678","status":"success","deployable":{"id":7,"commit":{"id":"99d03678","title":"Deploy"}}}}`)
+	})
+
+	env, _, err := client.Environments.GetEnvironment(ProjectID(1), 1)
+	if err != nil {
+		t.Fatalf("Environments.GetEnvironment returned error: %v", err)
+	}
+
+	d := env.LastDeployment
+	if d == nil || d.SHA != "99d03678" || d.Status != Success || d.Deployable.Commit.Title != "Deploy" {
+		t.Errorf("Environments.GetEnvironment returned %+v", env)
+	}
+}
+
+func TestCreateAndEditEnvironment(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/environments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"name":"staging"}`)
+		fmt.Fprint(w, `{"id":2,"name":"staging"}`)
+	})
+	mux.HandleFunc("/projects/1/environments/2", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testBody(t, r, `{"external_url":"https://staging.example.com"}`)
+		fmt.Fprint(w, `{"id":2,"name":"staging","external_url":"https://staging.example.com"}`)
+	})
+
+	env, _, err := client.Environments.CreateEnvironment(ProjectID(1), &CreateEnvironmentOptions{Name: "staging"})
+	if err != nil {
+		t.Fatalf("Environments.CreateEnvironment returned error: %v", err)
+	}
+
+	opt := &EditEnvironmentOptions{ExternalURL: "https://staging.example.com"}
+	env, _, err = client.Environments.EditEnvironment(ProjectID(1), env.ID, opt)
+	if err != nil {
+		t.Fatalf("Environments.EditEnvironment returned error: %v", err)
+	}
+
+	if env.ExternalURL != opt.ExternalURL {
+		t.Errorf("Environments.EditEnvironment returned %+v", env)
+	}
+}
+
+func TestStopAndDeleteEnvironment(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/environments/2/stop", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"id":2,"state":"stopped"}`)
+	})
+	mux.HandleFunc("/projects/1/environments/2", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	env, _, err := client.Environments.StopEnvironment(ProjectID(1), 2)
+	if err != nil || env.State != "stopped" {
+		t.Errorf("Environments.StopEnvironment returned %+v, %v", env, err)
+	}
+
+	if _, err := client.Environments.DeleteEnvironment(ProjectID(1), 2); err != nil {
+		t.Errorf("Environments.DeleteEnvironment returned error: %v", err)
+	}
+}
@@ -18,6 +18,7 @@ package gitlab
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,6 +26,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -33,10 +35,25 @@ import (
 
 const (
 	libraryVersion = "0.1"
-	defaultBaseURL = "https://gitlab.com/api/v3/"
+	defaultBaseURL = "https://gitlab.com/api/v4/"
 	userAgent      = "go-gitlab/" + libraryVersion
 )
 
+// APIVersion represents a version of the GitLab API.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/v3_to_v4.html
+type APIVersion int
+
+// List of supported API versions
+const (
+	APIVersion3 APIVersion = 3
+	APIVersion4 APIVersion = 4
+)
+
+func (v APIVersion) String() string {
+	return "v" + strconv.Itoa(int(v))
+}
+
 // AccessLevel represents a permission level within GitLab.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/permissions/permissions.html
@@ -46,6 +63,7 @@ type AccessLevel int
 //
 // GitLab API docs: http://doc.gitlab.com/ce/permissions/permissions.html
 const (
+	NoPermissions        AccessLevel = 0
 	GuestPermissions     AccessLevel = 10
 	ReporterPermissions  AccessLevel = 20
 	DeveloperPermissions AccessLevel = 30
@@ -69,6 +87,19 @@ const (
 	MentionNotifications
 )
 
+// Visibility represents a visibility level within GitLab API v4, which
+// replaces the numeric VisibilityLevel of API v3.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/projects.html#project-visibility-level
+type Visibility string
+
+// List of available visibility values
+const (
+	VisibilityPrivate  Visibility = "private"
+	VisibilityInternal Visibility = "internal"
+	VisibilityPublic   Visibility = "public"
+)
+
 // VisibilityLevel represents a visibility level within GitLab.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/...?
@@ -93,32 +124,68 @@ type Client struct {
 	// should always be specified with a trailing slash.
 	baseURL *url.URL
 
-	// Private token used to make authenticated API calls.
-	token string
+	// Version of the API to talk. Determines the shape of the paths and
+	// parameters used by the services.
+	apiVersion APIVersion
+
+	// Authenticator used to make authenticated API calls, nil sends requests
+	// without credentials.
+	auth Authenticator
+
+	// Context used for all requests made through this client. Defaults to
+	// context.Background(), use WithContext to bind another one.
+	ctx context.Context
+
+	// Policy used to retry failed requests, nil disables retrying.
+	retry *RetryPolicy
+
+	// Cache used for conditional requests, nil disables caching.
+	cache Cache
+
+	// Limiter of the rate of requests, nil disables rate limiting.
+	limiter *RateLimiter
+
+	// Middleware wrapped around every request, outermost first.
+	middleware []Middleware
+
+	// Logger of the requests sent, nil disables logging.
+	logger Logger
+
+	// Handler of the unknown fields of responses, nil disables their
+	// detection.
+	unknownFields UnknownFieldsHandler
 
 	// User agent used when communicating with the GitLab API.
 	UserAgent string
 
 	// Services used for talking to different parts of the GitLab API.
-	Branches        *BranchesService
-	Commits         *CommitsService
-	DeployKeys      *DeployKeysService
-	Groups          *GroupsService
-	Issues          *IssuesService
-	Labels          *LabelsService
-	MergeRequests   *MergeRequestsService
-	Milestones      *MilestonesService
-	Namespaces      *NamespacesService
-	Notes           *NotesService
-	Projects        *ProjectsService
-	ProjectSnippets *ProjectSnippetsService
-	Repositories    *RepositoriesService
-	RepositoryFiles *RepositoryFilesService
-	Services        *ServicesService
-	Session         *SessionService
-	Settings        *SettingsService
-	SystemHooks     *SystemHooksService
-	Users           *UsersService
+	Branches          *BranchesService
+	Commits           *CommitsService
+	DeployKeys        *DeployKeysService
+	Deployments       *DeploymentsService
+	Environments      *EnvironmentsService
+	Groups            *GroupsService
+	Issues            *IssuesService
+	Jobs              *JobsService
+	Labels            *LabelsService
+	MergeRequests     *MergeRequestsService
+	Milestones        *MilestonesService
+	Namespaces        *NamespacesService
+	Notes             *NotesService
+	Pipelines         *PipelinesService
+	PipelineSchedules *PipelineSchedulesService
+	PipelineTriggers  *PipelineTriggersService
+	Projects          *ProjectsService
+	ProtectedTags     *ProtectedTagsService
+	ProjectSnippets   *ProjectSnippetsService
+	Releases          *ReleasesService
+	Repositories      *RepositoriesService
+	RepositoryFiles   *RepositoryFilesService
+	Services          *ServicesService
+	Session           *SessionService
+	Settings          *SettingsService
+	SystemHooks       *SystemHooksService
+	Users             *UsersService
 }
 
 // ListOptions specifies the optional parameters to various List methods that
@@ -129,34 +196,85 @@ type ListOptions struct {
 
 	// For paginated result sets, the number of results to include per page.
 	PerPage int `url:"per_page,omitempty" json:"per_page,omitempty"`
+
+	// For paginated result sets, set to "keyset" to use keyset pagination:
+	// pages are then retrieved using the NextCursor of the previous response
+	// instead of their number, which keeps large listings fast and
+	// consistent. Supported by some endpoints only, like the projects,
+	// groups and repository tree listings, usually when ordering by ID or
+	// name.
+	Pagination string `url:"pagination,omitempty" json:"pagination,omitempty"`
+
+	// For keyset pagination, the cursor of the page of results to retrieve.
+	Cursor Cursor `url:"cursor,omitempty" json:"-"`
+}
+
+// Cursor holds the parameters of the link to a page of results when using
+// keyset pagination, e.g. id_after=42, or cursor=... for newer versions of
+// GitLab. They are sent along with the other parameters of the request.
+type Cursor url.Values
+
+// EncodeValues implements the query.Encoder interface. The parameters of the
+// cursor replace those already encoded with the same name, like order_by or
+// per_page, which the link to the next page repeats.
+func (c Cursor) EncodeValues(_ string, v *url.Values) error {
+	for k, values := range c {
+		v.Del(k)
+		for _, value := range values {
+			v.Add(k, value)
+		}
+	}
+	return nil
 }
 
 // NewClient returns a new GitLab API client. If a nil httpClient is
 // provided, http.DefaultClient will be used. To use API methods which require
-// authentication, provide a valid private token.
+// authentication, provide a valid private token, or use SetAuth to select
+// another authentication method.
 func NewClient(httpClient *http.Client, token string) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
-	c := &Client{client: httpClient, token: token, UserAgent: userAgent}
+	c := &Client{
+		client:    httpClient,
+		ctx:       context.Background(),
+		UserAgent: userAgent,
+	}
+	if token != "" {
+		c.auth = PrivateToken(token)
+	}
 	if err := c.SetBaseURL(defaultBaseURL); err != nil {
 		// should never happen since defaultBaseURL is our constant
 		panic(err)
 	}
+	c.setServices()
+
+	return c
+}
 
+// setServices (re)binds all services to c.
+func (c *Client) setServices() {
 	c.Branches = &BranchesService{client: c}
 	c.Commits = &CommitsService{client: c}
 	c.DeployKeys = &DeployKeysService{client: c}
+	c.Deployments = &DeploymentsService{client: c}
+	c.Environments = &EnvironmentsService{client: c}
 	c.Groups = &GroupsService{client: c}
 	c.Issues = &IssuesService{client: c}
+	c.Jobs = &JobsService{client: c}
 	c.Labels = &LabelsService{client: c}
 	c.MergeRequests = &MergeRequestsService{client: c}
 	c.Milestones = &MilestonesService{client: c}
 	c.Notes = &NotesService{client: c}
 	c.Namespaces = &NamespacesService{client: c}
+	c.Pipelines = &PipelinesService{client: c}
+	c.PipelineSchedules = &PipelineSchedulesService{client: c}
+	c.PipelineTriggers = &PipelineTriggersService{client: c}
 	c.Projects = &ProjectsService{client: c}
+	c.ProtectedTags = &ProtectedTagsService{client: c}
 	c.ProjectSnippets = &ProjectSnippetsService{client: c}
+	c.Releases = &ReleasesService{client: c}
 	c.Repositories = &RepositoriesService{client: c}
 	c.RepositoryFiles = &RepositoryFilesService{client: c}
 	c.Services = &ServicesService{client: c}
@@ -164,8 +282,30 @@ func NewClient(httpClient *http.Client, token string) *Client {
 	c.Settings = &SettingsService{client: c}
 	c.SystemHooks = &SystemHooksService{client: c}
 	c.Users = &UsersService{client: c}
+}
 
-	return c
+// WithContext returns a shallow copy of c that uses ctx for every request
+// made through its services. Cancelling ctx, or letting its deadline pass,
+// aborts any request that is still in flight.
+//
+// The returned client shares its underlying HTTP client with c, so it is
+// cheap enough to create one per incoming webhook or job.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	c2 := new(Client)
+	*c2 = *c
+	c2.ctx = ctx
+	c2.setServices()
+
+	return c2
+}
+
+// Context returns the context used for requests made through c.
+func (c *Client) Context() context.Context {
+	return c.ctx
 }
 
 // BaseURL return a copy of the baseURL.
@@ -175,36 +315,116 @@ func (c *Client) BaseURL() *url.URL {
 }
 
 // SetBaseURL sets the base URL for API requests to a custom endpoint. urlStr
-// should always be specified with a trailing slash.
+// should always be specified with a trailing slash. If urlStr ends with the
+// path of a known API version (e.g. "/api/v3/"), that version will be used.
 func (c *Client) SetBaseURL(urlStr string) error {
 	// Make sure the given URL end with a slash
 	if !strings.HasSuffix(urlStr, "/") {
 		urlStr += "/"
 	}
 
-	var err error
-	c.baseURL, err = url.Parse(urlStr)
+	baseURL, err := url.Parse(urlStr)
 	if err != nil {
 		return err
 	}
+	c.baseURL = baseURL
+
+	if v, ok := apiVersionOf(baseURL.Path); ok {
+		c.apiVersion = v
+	}
 
 	return nil
 }
 
+// APIVersion returns the version of the API used by the Client.
+func (c *Client) APIVersion() APIVersion {
+	return c.apiVersion
+}
+
+// SetAPIVersion sets the version of the API used by the Client. If the base
+// URL ends with the path of another API version, it is updated as well.
+func (c *Client) SetAPIVersion(v APIVersion) error {
+	if v != APIVersion3 && v != APIVersion4 {
+		return fmt.Errorf("unsupported API version: %s", v)
+	}
+
+	if old, ok := apiVersionOf(c.baseURL.Path); ok && old != v {
+		u := *c.baseURL
+		u.Path = strings.TrimSuffix(u.Path, apiPath(old)) + apiPath(v)
+		c.baseURL = &u
+	}
+	c.apiVersion = v
+
+	return nil
+}
+
+// apiPath returns the path suffix of the given API version.
+func apiPath(v APIVersion) string {
+	return "api/" + v.String() + "/"
+}
+
+// apiVersionOf returns the API version the given base path points at.
+func apiVersionOf(path string) (APIVersion, bool) {
+	for _, v := range []APIVersion{APIVersion3, APIVersion4} {
+		if strings.HasSuffix(path, "/"+apiPath(v)) {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
 // NewRequest creates an API request. A relative URL path can be provided in
 // urlStr, in which case it is resolved relative to the base URL of the Client.
 // Relative URL paths should always be specified without a preceding slash. If
 // specified, the value pointed to by body is JSON encoded and included as the
-// request body.
-func (c *Client) NewRequest(method, path string, opt interface{}) (*http.Request, error) {
-	u := *c.baseURL
-	// Set the encoded opaque data
-	u.Opaque = c.baseURL.Path + path
+// request body. The request uses the context of the Client. The options are
+// applied to the request after it has been built.
+func (c *Client) NewRequest(
+	method, path string,
+	opt interface{},
+	options ...OptionFunc) (*http.Request, error) {
+	return c.NewRequestWithContext(c.ctx, method, path, opt, options...)
+}
+
+// NewRequestWithContext is like NewRequest, but the returned request uses the
+// given context instead of the context of the Client.
+func (c *Client) NewRequestWithContext(
+	ctx context.Context,
+	method, path string,
+	opt interface{},
+	options ...OptionFunc) (*http.Request, error) {
+	if method == "POST" || method == "PUT" {
+		body, err := json.Marshal(opt)
+		if err != nil {
+			return nil, err
+		}
+		return c.newRequest(ctx, method, path, nil, body, "application/json", options)
+	}
 
 	q, err := query.Values(opt)
 	if err != nil {
 		return nil, err
 	}
+
+	return c.newRequest(ctx, method, path, q, nil, "", options)
+}
+
+// newRequest creates an API request with the given query parameters and body
+// of the given content type.
+func (c *Client) newRequest(
+	ctx context.Context,
+	method, path string,
+	q url.Values,
+	body []byte,
+	contentType string,
+	options []OptionFunc) (*http.Request, error) {
+	if ctx == nil {
+		return nil, errors.New("nil context")
+	}
+
+	u := *c.baseURL
+	// Set the encoded opaque data
+	u.Opaque = c.baseURL.Path + path
 	u.RawQuery = q.Encode()
 
 	req := &http.Request{
@@ -217,25 +437,27 @@ func (c *Client) NewRequest(method, path string, opt interface{}) (*http.Request
 		Host:       u.Host,
 	}
 
-	if method == "POST" || method == "PUT" {
-		bodyBytes, err := json.Marshal(opt)
-		if err != nil {
-			return nil, err
+	if body != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
 		}
-		bodyReader := bytes.NewReader(bodyBytes)
-
-		u.RawQuery = ""
-		req.Body = ioutil.NopCloser(bodyReader)
-		req.ContentLength = int64(bodyReader.Len())
-		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("PRIVATE-TOKEN", c.token)
 	if c.UserAgent != "" {
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
 
+	req = req.WithContext(ctx)
+	for _, fn := range options {
+		if err := fn(req); err != nil {
+			return nil, err
+		}
+	}
+
 	return req, nil
 }
 
@@ -254,12 +476,24 @@ type Response struct {
 	PrevPage  int
 	FirstPage int
 	LastPage  int
+
+	// The cursor of the next page when using keyset pagination. It is nil
+	// on the last page.
+	NextCursor Cursor
+
+	// The rate limit status as reported by GitLab. All fields are set to
+	// their zero value if GitLab does not report it.
+	RateLimit RateLimit
+
+	// Whether the response was served from the cache of the Client.
+	Cache CacheStatus
 }
 
 // newResponse creats a new Response for the provided http.Response.
 func newResponse(r *http.Response) *Response {
 	response := &Response{Response: r}
 	response.populatePageValues()
+	response.RateLimit = parseRateLimit(r.Header)
 	return response
 }
 
@@ -285,8 +519,13 @@ func (r *Response) populatePageValues() {
 			if err != nil {
 				continue
 			}
-			page := url.Query().Get("page")
+			query := url.Query()
+			page := query.Get("page")
 			if page == "" {
+				// Keyset pagination links carry a cursor instead of a page.
+				if hasRel(segments[1:], `rel="next"`) {
+					r.NextCursor = Cursor(query)
+				}
 				continue
 			}
 
@@ -307,20 +546,46 @@ func (r *Response) populatePageValues() {
 	}
 }
 
+// hasRel reports whether the parameters of a link hold the given rel.
+func hasRel(params []string, rel string) bool {
+	for _, p := range params {
+		if strings.TrimSpace(p) == rel {
+			return true
+		}
+	}
+	return false
+}
+
 // Do sends an API request and returns the API response. The API response is
 // JSON decoded and stored in the value pointed to by v, or returned as an
 // error if an API error has occurred. If v implements the io.Writer
 // interface, the raw response body will be written to v, without attempting to
 // first decode it.
+//
+// The request is aborted when its context is cancelled or its deadline is
+// exceeded, in which case the context's error is returned. Failed requests
+// are retried according to the retry policy of the Client, and GET requests
+// are served from its cache when GitLab reports them as not modified. The
+// middleware of the Client is wrapped around all of this.
 func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
-	resp, err := c.client.Do(req)
-	if err != nil {
+	if err := c.authenticate(req); err != nil {
 		return nil, err
 	}
 
+	var cache CacheStatus
+	resp, err := c.chain(func(req *http.Request) (*http.Response, error) {
+		resp, status, err := c.sendCached(req)
+		cache = status
+		return resp, err
+	})(req)
+	if err != nil {
+		return nil, contextErr(req, err)
+	}
+
 	defer resp.Body.Close()
 
 	response := newResponse(resp)
+	response.Cache = cache
 
 	err = CheckResponse(resp)
 	if err != nil {
@@ -332,6 +597,8 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
 			_, err = io.Copy(w, resp.Body)
+		} else if c.unknownFields != nil {
+			err = c.decodeStrict(req, resp, v)
 		} else {
 			err = json.NewDecoder(resp.Body).Decode(v)
 		}
@@ -339,8 +606,105 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 	return response, err
 }
 
-// Helper function to accept and format both the project ID or name as project
-// identifier for all API calls.
+// doStream sends an API request like Do, but leaves the body of the response
+// for the caller to read and close. The cache of the Client is not used, so
+// large downloads are never kept in memory.
+func (c *Client) doStream(req *http.Request) (*Response, error) {
+	if err := c.authenticate(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.chain(c.send)(req)
+	if err != nil {
+		return nil, contextErr(req, err)
+	}
+
+	response := newResponse(resp)
+
+	err = CheckResponse(resp)
+	if err != nil {
+		resp.Body.Close()
+		return response, err
+	}
+
+	return response, nil
+}
+
+// contextErr returns the error of the context of req if it is done, as it is
+// probably more useful than err, the error sending req.
+func contextErr(req *http.Request, err error) error {
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	default:
+	}
+	return err
+}
+
+// DoWithContext is like Do, but sends req with the given context instead of
+// the context it was created with.
+func (c *Client) DoWithContext(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
+	if ctx == nil {
+		return nil, errors.New("nil context")
+	}
+	return c.Do(req.WithContext(ctx), v)
+}
+
+// ProjectRef identifies a project in API calls. It is either a ProjectID, a
+// ProjectPath, or a *Project:
+//
+//	git.Projects.GetProject(gitlab.ProjectID(42))
+//	git.Projects.GetProject(gitlab.ProjectPath("group/subgroup/project"))
+//	git.Issues.ListProjectIssues(project, nil)
+type ProjectRef interface {
+	// projectRef returns the escaped path segment identifying the project.
+	projectRef() (string, error)
+}
+
+// ProjectID is a ProjectRef holding the ID of a project.
+type ProjectID int
+
+func (id ProjectID) projectRef() (string, error) {
+	return strconv.Itoa(int(id)), nil
+}
+
+// ProjectPath is a ProjectRef holding the path with namespace of a project,
+// e.g. "group/subgroup/project".
+type ProjectPath string
+
+func (path ProjectPath) projectRef() (string, error) {
+	if path == "" {
+		return "", errors.New("empty project path")
+	}
+	return url.PathEscape(string(path)), nil
+}
+
+// projectRef identifies p by its ID, or by its path with namespace when it
+// has no ID.
+func (p *Project) projectRef() (string, error) {
+	switch {
+	case p == nil:
+		return "", errors.New("nil project")
+	case p.ID != nil:
+		return ProjectID(*p.ID).projectRef()
+	case p.PathWithNamespace != nil:
+		return ProjectPath(*p.PathWithNamespace).projectRef()
+	default:
+		return "", errors.New("the project has no ID nor path")
+	}
+}
+
+// parseProject returns the escaped path segment identifying the project pid,
+// to be used as is in the path of an API call.
+func parseProject(pid ProjectRef) (string, error) {
+	if pid == nil {
+		return "", errors.New("nil project reference")
+	}
+	return pid.projectRef()
+}
+
+// Helper function to accept and format both the ID or name of groups and
+// users as identifier for API calls.
 func parseID(id interface{}) (string, error) {
 	switch v := id.(type) {
 	case int:
@@ -352,6 +716,36 @@ func parseID(id interface{}) (string, error) {
 	}
 }
 
+// Errors that an ErrorResponse matches, depending on the status code of the
+// response, when using errors.Is:
+//
+//	if errors.Is(err, gitlab.ErrNotFound) {
+//		...
+//	}
+var (
+	// ErrValidation is matched by 400 Bad Request and 422 Unprocessable
+	// Entity responses. The Errors of the ErrorResponse hold the details.
+	ErrValidation = errors.New("validation failed")
+
+	// ErrUnauthorized is matched by 401 Unauthorized responses.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden is matched by 403 Forbidden responses.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrNotFound is matched by 404 Not Found responses.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is matched by 405 Method Not Allowed, 406 Not Acceptable
+	// and 409 Conflict responses, which GitLab returns when a request
+	// conflicts with the state of a resource, e.g. when accepting a merge
+	// request that cannot be merged.
+	ErrConflict = errors.New("conflict")
+
+	// ErrRateLimited is matched by 429 Too Many Requests responses.
+	ErrRateLimited = errors.New("rate limited")
+)
+
 // An ErrorResponse reports one or more errors caused by an API request.
 //
 // GitLab API docs:
@@ -370,6 +764,94 @@ func (r *ErrorResponse) Error() string {
 		r.Response.Request.Method, ru, r.Response.StatusCode, r.Message, r.Errors)
 }
 
+// Is reports whether r matches target, which is one of the Err* errors of
+// this package.
+func (r *ErrorResponse) Is(target error) bool {
+	switch r.Response.StatusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return target == ErrValidation
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	}
+	return false
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. GitLab returns the
+// message of an error as a string, as an array of strings, or as an object
+// which maps the fields that failed validation to their messages. The latter
+// are also added to Errors.
+func (r *ErrorResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+		Errors  []Error     `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	msgs, errs := parseErrorMessage("", raw.Message)
+	r.Message = strings.Join(msgs, ", ")
+	if r.Message == "" {
+		// Some endpoints, e.g. the OAuth ones, report errors using an error
+		// field instead.
+		r.Message = raw.Error
+	}
+	r.Errors = append(raw.Errors, errs...)
+
+	return nil
+}
+
+// parseErrorMessage flattens the (part of an) error message v which concerns
+// field into a list of messages, and a list of errors for the fields it
+// mentions.
+func parseErrorMessage(field string, v interface{}) ([]string, []Error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		var msgs []string
+		var errs []Error
+		for _, e := range v {
+			m, e := parseErrorMessage(field, e)
+			msgs, errs = append(msgs, m...), append(errs, e...)
+		}
+		return msgs, errs
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var msgs []string
+		var errs []Error
+		for _, k := range keys {
+			f := k
+			if field != "" {
+				f = field + "." + k
+			}
+			m, e := parseErrorMessage(f, v[k])
+			msgs, errs = append(msgs, m...), append(errs, e...)
+		}
+		return msgs, errs
+	default:
+		msg := fmt.Sprint(v)
+		if field == "" {
+			return []string{msg}, nil
+		}
+		return []string{field + " " + msg}, []Error{{Field: field, Message: msg}}
+	}
+}
+
 // An Error reports more details on an individual error in an ErrorResponse.
 // These are the possible validation error codes:
 //
@@ -388,9 +870,13 @@ type Error struct {
 	Resource string `json:"resource"` // resource on which the error occurred
 	Field    string `json:"field"`    // field on which the error occurred
 	Code     string `json:"code"`     // validation error code
+	Message  string `json:"message"`  // validation error message
 }
 
 func (e *Error) Error() string {
+	if e.Code == "" && e.Message != "" {
+		return fmt.Sprintf("%v %v", e.Field, e.Message)
+	}
 	return fmt.Sprintf("%v error caused by %v field on %v resource",
 		e.Code, e.Field, e.Resource)
 }
@@ -436,3 +922,11 @@ func String(v string) *string {
 	*p = v
 	return p
 }
+
+// Access is a helper routine that allocates a new AccessLevel value
+// to store v and returns a pointer to it.
+func Access(v AccessLevel) *AccessLevel {
+	p := new(AccessLevel)
+	*p = v
+	return p
+}
@@ -1,7 +1,9 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -10,6 +12,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // setup sets up a test HTTP server along with a gitlab.Client that is
@@ -29,6 +32,14 @@ func setup() (*http.ServeMux, *httptest.Server, *Client) {
 	return mux, server, client
 }
 
+// setupV3 is like setup, but the returned client talks API v3.
+func setupV3() (*http.ServeMux, *httptest.Server, *Client) {
+	mux, server, client := setup()
+	client.SetAPIVersion(APIVersion3)
+
+	return mux, server, client
+}
+
 // teardown closes the test HTTP server.
 func teardown(server *httptest.Server) {
 	server.Close()
@@ -106,6 +117,119 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestCheckResponse_messageObject(t *testing.T) {
+	res := &http.Response{
+		Request:    &http.Request{},
+		StatusCode: http.StatusBadRequest,
+		Body: ioutil.NopCloser(strings.NewReader(`{"message":{
+			"path":["is too short","is invalid"],
+			"name":["has already been taken"]}}`)),
+	}
+	err := CheckResponse(res).(*ErrorResponse)
+
+	want := &ErrorResponse{
+		Response: res,
+		Message:  "name has already been taken, path is too short, path is invalid",
+		Errors: []Error{
+			{Field: "name", Message: "has already been taken"},
+			{Field: "path", Message: "is too short"},
+			{Field: "path", Message: "is invalid"},
+		},
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("Error = %#v, want %#v", err, want)
+	}
+}
+
+func TestCheckResponse_messageArray(t *testing.T) {
+	res := &http.Response{
+		Request:    &http.Request{},
+		StatusCode: http.StatusBadRequest,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"message":["first","second"]}`)),
+	}
+	err := CheckResponse(res).(*ErrorResponse)
+
+	want := &ErrorResponse{Response: res, Message: "first, second"}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("Error = %#v, want %#v", err, want)
+	}
+}
+
+func TestCheckResponse_errorField(t *testing.T) {
+	res := &http.Response{
+		Request:    &http.Request{},
+		StatusCode: http.StatusBadRequest,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"error":"title is missing"}`)),
+	}
+	err := CheckResponse(res).(*ErrorResponse)
+
+	if want := "title is missing"; err.Message != want {
+		t.Errorf("Error.Message = %q, want %q", err.Message, want)
+	}
+}
+
+func TestErrorResponse_Is(t *testing.T) {
+	sentinels := []error{ErrValidation, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrRateLimited}
+	for code, want := range map[int]error{
+		http.StatusBadRequest:          ErrValidation,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusMethodNotAllowed:    ErrConflict,
+		http.StatusNotAcceptable:       ErrConflict,
+		http.StatusConflict:            ErrConflict,
+		http.StatusUnprocessableEntity: ErrValidation,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusInternalServerError: nil,
+	} {
+		var err error = &ErrorResponse{Response: &http.Response{StatusCode: code}}
+		err = fmt.Errorf("wrapped: %w", err)
+
+		for _, target := range sentinels {
+			if got := errors.Is(err, target); got != (target == want) {
+				t.Errorf("errors.Is(%d, %v) = %t, want %t", code, target, got, !got)
+			}
+		}
+	}
+}
+
+func TestSetBaseURL_apiVersion(t *testing.T) {
+	c := NewClient(nil, "")
+
+	if c.APIVersion() != APIVersion4 {
+		t.Errorf("NewClient APIVersion is %s, want %s", c.APIVersion(), APIVersion4)
+	}
+
+	c.SetBaseURL("https://gitlab.example.com/api/v3")
+	if c.APIVersion() != APIVersion3 {
+		t.Errorf("APIVersion is %s after setting a v3 base URL, want %s", c.APIVersion(), APIVersion3)
+	}
+
+	c.SetBaseURL("https://gitlab.example.com/")
+	if c.APIVersion() != APIVersion3 {
+		t.Errorf("APIVersion is %s after setting a base URL without version, want %s", c.APIVersion(), APIVersion3)
+	}
+}
+
+func TestSetAPIVersion(t *testing.T) {
+	c := NewClient(nil, "")
+	c.SetBaseURL("https://gitlab.example.com/api/v4/")
+
+	if err := c.SetAPIVersion(APIVersion3); err != nil {
+		t.Fatalf("SetAPIVersion returned error: %v", err)
+	}
+	if c.APIVersion() != APIVersion3 {
+		t.Errorf("APIVersion is %s, want %s", c.APIVersion(), APIVersion3)
+	}
+	if want := "https://gitlab.example.com/api/v3/"; c.BaseURL().String() != want {
+		t.Errorf("BaseURL is %s, want %s", c.BaseURL().String(), want)
+	}
+
+	if err := c.SetAPIVersion(5); err == nil {
+		t.Errorf("SetAPIVersion(5) returned no error")
+	}
+}
+
 func TestCheckResponse(t *testing.T) {
 	res := &http.Response{
 		Request:    &http.Request{},
@@ -149,3 +273,114 @@ func TestSetBaseURL(t *testing.T) {
 		t.Errorf("Failed setting base url. expecting: %s got: %s", expected, client.baseURL.String())
 	}
 }
+
+func TestWithContext(t *testing.T) {
+	type key struct{}
+
+	c := NewClient(nil, "")
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	cc := c.WithContext(ctx)
+	if cc.Context() != ctx {
+		t.Errorf("WithContext Context is %v, want %v", cc.Context(), ctx)
+	}
+	if c.Context() != context.Background() {
+		t.Errorf("WithContext changed the context of the original client")
+	}
+	if cc.Users.client != cc {
+		t.Errorf("WithContext did not rebind the services")
+	}
+
+	req, err := cc.NewRequest("GET", "user", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	if req.Context() != ctx {
+		t.Errorf("NewRequest context is %v, want %v", req.Context(), ctx)
+	}
+}
+
+func TestDo_slowHandlerAborted(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			fmt.Fprint(w, `{"id":1}`)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := client.WithContext(ctx).Users.CurrentUser()
+	if err != context.DeadlineExceeded {
+		t.Errorf("Users.CurrentUser returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Users.CurrentUser took %v, request was not aborted", elapsed)
+	}
+}
+
+func TestDoWithContext_cancelled(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request should not have been sent")
+	})
+
+	req, err := client.NewRequest("GET", "user", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.DoWithContext(ctx, req, nil)
+	if err != context.Canceled {
+		t.Errorf("DoWithContext returned error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestProjectRef(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":1,"path_with_namespace":%q}`, r.RequestURI)
+	})
+
+	path, id := "group/sub project/project", 1
+	tests := []struct {
+		pid  ProjectRef
+		want string
+	}{
+		{ProjectID(42), "/projects/42"},
+		{ProjectPath("group/subgroup/project"), "/projects/group%2Fsubgroup%2Fproject"},
+		{ProjectPath(path), "/projects/group%2Fsub%20project%2Fproject"},
+		{&Project{ID: &id, PathWithNamespace: &path}, "/projects/1"},
+		{&Project{PathWithNamespace: &path}, "/projects/group%2Fsub%20project%2Fproject"},
+	}
+
+	for _, tt := range tests {
+		project, _, err := client.Projects.GetProject(tt.pid)
+		if err != nil {
+			t.Errorf("Projects.GetProject(%v) returned error: %v", tt.pid, err)
+			continue
+		}
+		if *project.PathWithNamespace != tt.want {
+			t.Errorf("Projects.GetProject(%v) requested %s, want %s", tt.pid, *project.PathWithNamespace, tt.want)
+		}
+	}
+
+	for _, pid := range []ProjectRef{nil, ProjectPath(""), (*Project)(nil), &Project{}} {
+		if _, _, err := client.Projects.GetProject(pid); err == nil {
+			t.Errorf("Projects.GetProject(%#v) returned no error", pid)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlabtest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+// CheckFields fails t if the JSON payload holds fields which are not mapped
+// by v, the value the payload is decoded into (e.g. &gitlab.MergeRequest{}).
+// The error lists the fields, which tells the struct fields to add:
+//
+//	gitlabtest.CheckFields(t, payload, &[]*gitlab.Project{})
+func CheckFields(t testing.TB, payload []byte, v interface{}) {
+	t.Helper()
+
+	fields, err := gitlab.UnknownFields(payload, v)
+	if err != nil {
+		t.Errorf("gitlabtest: invalid payload: %v", err)
+		return
+	}
+	if len(fields) > 0 {
+		t.Errorf("gitlabtest: fields not mapped by %T: %s", v, strings.Join(fields, ", "))
+	}
+}
+
+// CheckFixture is like CheckFields, for the payload held by the file at path.
+func CheckFixture(t testing.TB, path string, v interface{}) {
+	t.Helper()
+
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("gitlabtest: %v", err)
+		return
+	}
+	CheckFields(t, payload, v)
+}
+
+// CheckRecording is like CheckFields, for the successful JSON responses of
+// the fixture file written by a Recorder at path. types maps the methods and
+// normalized endpoints of the requests to check to the values their
+// responses are decoded into, the other requests are skipped:
+//
+//	gitlabtest.CheckRecording(t, "testdata/issues.json", map[string]interface{}{
+//		"GET projects/:id/issues":  &[]*gitlab.Issue{},
+//		"POST projects/:id/issues": &gitlab.Issue{},
+//	})
+func CheckRecording(t testing.TB, path string, types map[string]interface{}) {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("gitlabtest: %v", err)
+		return
+	}
+	var interactions []*Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		t.Errorf("gitlabtest: invalid fixtures in %s: %v", path, err)
+		return
+	}
+
+	for _, in := range interactions {
+		key := in.Request.Method + " " + gitlab.NormalizeEndpoint(in.Request.URL)
+		v, ok := types[key]
+		if !ok || in.Response.StatusCode >= 300 || in.Response.Body == "" {
+			continue
+		}
+
+		fields, err := gitlab.UnknownFields([]byte(in.Response.Body), v)
+		if err != nil {
+			t.Errorf("gitlabtest: invalid response to %s %s: %v", in.Request.Method, in.Request.URL, err)
+			continue
+		}
+		if len(fields) > 0 {
+			t.Errorf("gitlabtest: fields of %s not mapped by %T: %s", key, v, strings.Join(fields, ", "))
+		}
+	}
+}
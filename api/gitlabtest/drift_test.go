@@ -0,0 +1,79 @@
+package gitlabtest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+// recordingT is a testing.TB recording its errors instead of failing.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckFields(t *testing.T) {
+	rt := &recordingT{TB: t}
+	CheckFields(rt, []byte(`{"id":1,"name":"bug","color":"red","description":"d"}`), &gitlab.Label{})
+	if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], "description") {
+		t.Errorf("CheckFields reported %v, want the description field", rt.errors)
+	}
+
+	rt = &recordingT{TB: t}
+	CheckFields(rt, []byte(`{"name":"bug","color":"red"}`), &gitlab.Label{})
+	if len(rt.errors) != 0 {
+		t.Errorf("CheckFields reported %v, want nothing", rt.errors)
+	}
+}
+
+func TestCheckRecording(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	fixtures := filepath.Join(t.TempDir(), "projects.json")
+	rec, err := NewRecorder(fixtures, Record)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	git := gitlab.NewClient(rec.Client(), RootToken)
+	git.SetBaseURL(srv.URL())
+
+	project, _, err := git.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: "demo"})
+	if err != nil {
+		t.Fatalf("Projects.CreateProject returned error: %v", err)
+	}
+	if _, _, err := git.Commits.ListCommits(project, nil); err != nil {
+		t.Fatalf("Commits.ListCommits returned error: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Recorder.Save returned error: %v", err)
+	}
+
+	// The fake server answers with the types of this package.
+	CheckRecording(t, fixtures, map[string]interface{}{
+		"POST projects":                       &gitlab.Project{},
+		"GET projects/:id/repository/commits": &[]*gitlab.Commit{},
+	})
+
+	// Types missing fields are reported.
+	type commit struct {
+		ID string `json:"id"`
+	}
+	rt := &recordingT{TB: t}
+	CheckRecording(rt, fixtures, map[string]interface{}{
+		"GET projects/:id/repository/commits": &[]*commit{},
+	})
+	if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], "short_id") {
+		t.Errorf("CheckRecording reported %v, want the short_id field", rt.errors)
+	}
+}
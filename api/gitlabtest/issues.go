@@ -0,0 +1,329 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlabtest
+
+import (
+	"strconv"
+	"strings"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+func (s *Server) issueParam(r *request) (*project, *gitlab.Issue, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	iid, err := r.intParam("iid")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, i := range p.issues {
+		if i.IID == iid {
+			return p, i, nil
+		}
+	}
+	return nil, nil, errNotFound("Issue")
+}
+
+// filterIssues returns the issues matching the iid, state and labels
+// parameters of r, newest first.
+func filterIssues(r *request, issues []*gitlab.Issue) []*gitlab.Issue {
+	q := r.URL.Query()
+	iid, _ := strconv.Atoi(q.Get("iid"))
+	state := q.Get("state")
+	labels := splitLabels(q["labels"])
+
+	filtered := []*gitlab.Issue{}
+	for j := len(issues) - 1; j >= 0; j-- {
+		i := issues[j]
+		if iid != 0 && i.IID != iid {
+			continue
+		}
+		if state != "" && state != "all" && i.State != state {
+			continue
+		}
+		if !hasLabels(i.Labels, labels) {
+			continue
+		}
+		filtered = append(filtered, i)
+	}
+
+	return filtered
+}
+
+// splitLabels splits the comma separated labels in values.
+func splitLabels(values []string) []string {
+	labels := []string{}
+	for _, v := range values {
+		for _, l := range strings.Split(v, ",") {
+			if l = strings.TrimSpace(l); l != "" {
+				labels = append(labels, l)
+			}
+		}
+	}
+	return labels
+}
+
+// hasLabels reports whether labels holds all of want.
+func hasLabels(labels, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, l := range labels {
+			found = found || l == w
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) listIssues(r *request) (interface{}, error) {
+	var issues []*gitlab.Issue
+	for _, p := range s.projects {
+		for _, i := range p.issues {
+			if i.Author.ID == r.user.ID {
+				issues = append(issues, i)
+			}
+		}
+	}
+	return filterIssues(r, issues), nil
+}
+
+func (s *Server) listProjectIssues(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return filterIssues(r, p.issues), nil
+}
+
+func (s *Server) getIssue(r *request) (interface{}, error) {
+	_, i, err := s.issueParam(r)
+	return i, err
+}
+
+func (s *Server) createIssue(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(gitlab.CreateIssueOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Title == "" {
+		return nil, errInvalid("title", "can't be blank")
+	}
+
+	now := s.now()
+	i := &gitlab.Issue{
+		ID:          s.nextID(),
+		IID:         p.nextIID(),
+		ProjectID:   p.id,
+		Title:       opt.Title,
+		Description: opt.Description,
+		Labels:      splitLabels(opt.Labels),
+		State:       "opened",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	setAuthor(&i.Author, r.user)
+	if err := s.assignIssue(i, opt.AssigneeID); err != nil {
+		return nil, err
+	}
+	p.issues = append(p.issues, i)
+
+	return i, nil
+}
+
+// updateIssueOptions are the options of an issue update. Unlike those of
+// gitlab.UpdateIssueOptions, the labels can be told apart from no labels.
+type updateIssueOptions struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	AssigneeID  int       `json:"assignee_id"`
+	Labels      *[]string `json:"labels"`
+	StateEvent  string    `json:"state_event"`
+}
+
+func (s *Server) updateIssue(r *request) (interface{}, error) {
+	_, i, err := s.issueParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(updateIssueOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Title != "" {
+		i.Title = opt.Title
+	}
+	if opt.Description != "" {
+		i.Description = opt.Description
+	}
+	if opt.Labels != nil {
+		i.Labels = splitLabels(*opt.Labels)
+	}
+	if opt.AssigneeID != 0 {
+		if err := s.assignIssue(i, opt.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+	switch opt.StateEvent {
+	case "close":
+		i.State = "closed"
+	case "reopen":
+		i.State = "opened"
+	}
+	i.UpdatedAt = s.now()
+
+	return i, nil
+}
+
+func (s *Server) assignIssue(i *gitlab.Issue, assigneeID int) error {
+	if assigneeID == 0 {
+		return nil
+	}
+	u := s.findUser(assigneeID)
+	if u == nil {
+		return errNotFound("User")
+	}
+	setAuthor(&i.Assignee, u)
+	return nil
+}
+
+// setAuthor sets the author (or assignee) of an issue or note to u.
+func setAuthor(author *struct {
+	ID        int         `json:"id"`
+	Username  string      `json:"username"`
+	Email     string      `json:"email"`
+	Name      string      `json:"name"`
+	State     string      `json:"state"`
+	CreatedAt gitlab.Time `json:"created_at"`
+}, u *gitlab.User) {
+	author.ID = u.ID
+	author.Username = u.Username
+	author.Email = u.Email
+	author.Name = u.Name
+	author.State = u.State
+	author.CreatedAt = u.CreatedAt
+}
+
+// notesKey returns the key of the notes of an issue or merge request.
+func notesKey(noteable string, iid int) string {
+	return noteable + "/" + strconv.Itoa(iid)
+}
+
+func (s *Server) listNotes(p *project, key string) (interface{}, error) {
+	return append([]*gitlab.Note{}, p.notes[key]...), nil
+}
+
+func (s *Server) getNote(r *request, p *project, key string) (interface{}, error) {
+	return s.findNote(r, p, key)
+}
+
+func (s *Server) findNote(r *request, p *project, key string) (*gitlab.Note, error) {
+	id, err := r.intParam("note_id")
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range p.notes[key] {
+		if n.ID == id {
+			return n, nil
+		}
+	}
+	return nil, errNotFound("Note")
+}
+
+func (s *Server) createNote(r *request, p *project, key string) (interface{}, error) {
+	opt := new(gitlab.CreateIssueNoteOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Body == "" {
+		return nil, errInvalid("body", "can't be blank")
+	}
+
+	now := s.now()
+	n := &gitlab.Note{
+		ID:        s.nextID(),
+		Body:      opt.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	setAuthor(&n.Author, r.user)
+	p.notes[key] = append(p.notes[key], n)
+
+	return n, nil
+}
+
+func (s *Server) updateNote(r *request, p *project, key string) (interface{}, error) {
+	n, err := s.findNote(r, p, key)
+	if err != nil {
+		return nil, err
+	}
+	if n.Author.ID != r.user.ID && !r.user.IsAdmin {
+		return nil, errForbidden()
+	}
+
+	opt := new(gitlab.UpdateIssueNoteOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Body != "" {
+		n.Body = opt.Body
+	}
+	n.UpdatedAt = s.now()
+
+	return n, nil
+}
+
+func (s *Server) listIssueNotes(r *request) (interface{}, error) {
+	p, i, err := s.issueParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.listNotes(p, notesKey("issue", i.IID))
+}
+
+func (s *Server) getIssueNote(r *request) (interface{}, error) {
+	p, i, err := s.issueParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.getNote(r, p, notesKey("issue", i.IID))
+}
+
+func (s *Server) createIssueNote(r *request) (interface{}, error) {
+	p, i, err := s.issueParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.createNote(r, p, notesKey("issue", i.IID))
+}
+
+func (s *Server) updateIssueNote(r *request) (interface{}, error) {
+	p, i, err := s.issueParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateNote(r, p, notesKey("issue", i.IID))
+}
@@ -0,0 +1,238 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlabtest
+
+import (
+	"fmt"
+	"strconv"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+func (s *Server) mergeRequestParam(r *request) (*project, *gitlab.MergeRequest, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	iid, err := r.intParam("iid")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, mr := range p.mrs {
+		if mr.IID == iid {
+			return p, mr, nil
+		}
+	}
+	return nil, nil, errNotFound("Merge Request")
+}
+
+func (s *Server) listMergeRequests(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	q := r.URL.Query()
+	state := q.Get("state")
+	iid, _ := strconv.Atoi(q.Get("iid"))
+
+	mrs := []*gitlab.MergeRequest{}
+	for j := len(p.mrs) - 1; j >= 0; j-- {
+		mr := p.mrs[j]
+		if state != "" && state != "all" && mr.State != state {
+			continue
+		}
+		if iid != 0 && mr.IID != iid {
+			continue
+		}
+		mrs = append(mrs, mr)
+	}
+
+	return mrs, nil
+}
+
+func (s *Server) getMergeRequest(r *request) (interface{}, error) {
+	_, mr, err := s.mergeRequestParam(r)
+	return mr, err
+}
+
+func (s *Server) createMergeRequest(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(gitlab.CreateMergeRequestOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	switch {
+	case opt.Title == "":
+		return nil, errInvalid("title", "can't be blank")
+	case p.branches[opt.SourceBranch] == nil:
+		return nil, errInvalid("source_branch", "is invalid")
+	case p.branches[opt.TargetBranch] == nil:
+		return nil, errInvalid("target_branch", "is invalid")
+	case opt.SourceBranch == opt.TargetBranch:
+		return nil, errInvalid("branch_conflict", "You can not use same project/branch for source and target")
+	}
+
+	now := s.now()
+	mr := &gitlab.MergeRequest{
+		ID:              s.nextID(),
+		IID:             p.nextIID(),
+		ProjectID:       p.id,
+		Title:           opt.Title,
+		Description:     opt.Description,
+		SourceBranch:    opt.SourceBranch,
+		TargetBranch:    opt.TargetBranch,
+		SourceProjectID: p.id,
+		TargetProjectID: p.id,
+		State:           "opened",
+		Labels:          []string{},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	setMergeRequestUser(&mr.Author, r.user)
+	if err := s.assignMergeRequest(mr, opt.AssigneeID); err != nil {
+		return nil, err
+	}
+	p.mrs = append(p.mrs, mr)
+
+	return mr, nil
+}
+
+func (s *Server) updateMergeRequest(r *request) (interface{}, error) {
+	p, mr, err := s.mergeRequestParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(gitlab.UpdateMergeRequestOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Title != "" {
+		mr.Title = opt.Title
+	}
+	if opt.Description != "" {
+		mr.Description = opt.Description
+	}
+	if opt.TargetBranch != "" {
+		if p.branches[opt.TargetBranch] == nil {
+			return nil, errInvalid("target_branch", "is invalid")
+		}
+		mr.TargetBranch = opt.TargetBranch
+	}
+	if err := s.assignMergeRequest(mr, opt.AssigneeID); err != nil {
+		return nil, err
+	}
+	switch {
+	case opt.StateEvent == "close" && mr.State == "opened":
+		mr.State = "closed"
+	case opt.StateEvent == "reopen" && mr.State == "closed":
+		mr.State = "opened"
+	}
+	mr.UpdatedAt = s.now()
+
+	return mr, nil
+}
+
+// acceptMergeRequest merges the source branch of a merge request into its
+// target branch, using a merge commit.
+func (s *Server) acceptMergeRequest(r *request) (interface{}, error) {
+	p, mr, err := s.mergeRequestParam(r)
+	if err != nil {
+		return nil, err
+	}
+	if mr.State != "opened" {
+		return nil, errNotAllowed()
+	}
+
+	source, target := p.branches[mr.SourceBranch], p.branches[mr.TargetBranch]
+	if source == nil || target == nil {
+		return nil, &apiError{406, "Branch cannot be merged"}
+	}
+
+	message := fmt.Sprintf("Merge branch '%s' into '%s'\n\n%s", mr.SourceBranch, mr.TargetBranch, mr.Title)
+	c := s.commit(p, mr.TargetBranch, r.user, message)
+	c.ParentsIds = append(c.ParentsIds, source.Commit.ID)
+
+	mr.State = "merged"
+	mr.UpdatedAt = s.now()
+
+	return mr, nil
+}
+
+func (s *Server) assignMergeRequest(mr *gitlab.MergeRequest, assigneeID int) error {
+	if assigneeID == 0 {
+		return nil
+	}
+	u := s.findUser(assigneeID)
+	if u == nil {
+		return errNotFound("User")
+	}
+	setMergeRequestUser(&mr.Assignee, u)
+	return nil
+}
+
+// setMergeRequestUser sets the author or assignee of a merge request to u.
+func setMergeRequestUser(mu *struct {
+	Name      string `json:"name"`
+	Username  string `json:"username"`
+	ID        int    `json:"id"`
+	State     string `json:"state"`
+	AvatarURL string `json:"avatar_url"`
+}, u *gitlab.User) {
+	mu.Name = u.Name
+	mu.Username = u.Username
+	mu.ID = u.ID
+	mu.State = u.State
+	mu.AvatarURL = u.AvatarURL
+}
+
+func (s *Server) listMergeRequestNotes(r *request) (interface{}, error) {
+	p, mr, err := s.mergeRequestParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.listNotes(p, notesKey("merge_request", mr.IID))
+}
+
+func (s *Server) getMergeRequestNote(r *request) (interface{}, error) {
+	p, mr, err := s.mergeRequestParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.getNote(r, p, notesKey("merge_request", mr.IID))
+}
+
+func (s *Server) createMergeRequestNote(r *request) (interface{}, error) {
+	p, mr, err := s.mergeRequestParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.createNote(r, p, notesKey("merge_request", mr.IID))
+}
+
+func (s *Server) updateMergeRequestNote(r *request) (interface{}, error) {
+	p, mr, err := s.mergeRequestParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateNote(r, p, notesKey("merge_request", mr.IID))
+}
@@ -0,0 +1,304 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlabtest
+
+import (
+	"strconv"
+	"strings"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+// project holds a project and everything that belongs to it.
+type project struct {
+	gitlab.Project
+
+	id       int
+	lastIID  int
+	issues   []*gitlab.Issue
+	mrs      []*gitlab.MergeRequest
+	notes    map[string][]*gitlab.Note
+	branches map[string]*gitlab.Branch
+	commits  map[string]*gitlab.Commit
+	comments map[string][]*gitlab.CommitComment
+	hooks    []*gitlab.ProjectHook
+}
+
+// nextIID returns a new IID, unique among the issues and merge requests of
+// p. GitLab numbers them separately, but sharing the sequence is harmless
+// and catches code mixing them up.
+func (p *project) nextIID() int {
+	p.lastIID++
+	return p.lastIID
+}
+
+// findProject returns the project with the given ID or path with namespace.
+func (s *Server) findProject(pid string) *project {
+	id, err := strconv.Atoi(pid)
+	for _, p := range s.projects {
+		if err == nil && p.id == id || err != nil && *p.PathWithNamespace == pid {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) projectParam(r *request) (*project, error) {
+	p := s.findProject(r.params["id"])
+	if p == nil {
+		return nil, errNotFound("Project")
+	}
+	return p, nil
+}
+
+func (s *Server) listProjects(r *request) (interface{}, error) {
+	q := r.URL.Query()
+	search := strings.ToLower(q.Get("search"))
+	owned := q.Get("owned") == "true"
+
+	projects := []*gitlab.Project{}
+	for _, p := range s.projects {
+		if owned && p.Owner.ID != r.user.ID {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(*p.Name), search) {
+			continue
+		}
+		projects = append(projects, &p.Project)
+	}
+
+	return projects, nil
+}
+
+func (s *Server) getProject(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return &p.Project, nil
+}
+
+func (s *Server) createProject(r *request) (interface{}, error) {
+	opt := new(gitlab.CreateProjectOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Name == "" && opt.Path == "" {
+		return nil, errInvalid("name", "can't be blank")
+	}
+	if opt.Name == "" {
+		opt.Name = opt.Path
+	}
+	if opt.Path == "" {
+		opt.Path = strings.ToLower(strings.Replace(opt.Name, " ", "-", -1))
+	}
+	if opt.Visibility == "" {
+		opt.Visibility = gitlab.VisibilityPrivate
+	}
+
+	pathWithNamespace := r.user.Username + "/" + opt.Path
+	if s.findProject(pathWithNamespace) != nil {
+		return nil, errInvalid("path", "has already been taken")
+	}
+
+	id := s.nextID()
+	now := s.now()
+	owner := *r.user
+	p := &project{
+		Project: gitlab.Project{
+			ID:                   gitlab.Int(id),
+			Name:                 gitlab.String(opt.Name),
+			NameWithNamespace:    gitlab.String(r.user.Name + " / " + opt.Name),
+			Path:                 gitlab.String(opt.Path),
+			PathWithNamespace:    gitlab.String(pathWithNamespace),
+			Description:          gitlab.String(opt.Description),
+			DefaultBranch:        gitlab.String("master"),
+			Visibility:           &opt.Visibility,
+			Public:               gitlab.Bool(opt.Visibility == gitlab.VisibilityPublic),
+			WebURL:               gitlab.String(s.srv.URL + "/" + pathWithNamespace),
+			HTTPURLToRepo:        gitlab.String(s.srv.URL + "/" + pathWithNamespace + ".git"),
+			SSHURLToRepo:         gitlab.String("git@" + s.srv.Listener.Addr().String() + ":" + pathWithNamespace + ".git"),
+			Owner:                &owner,
+			CreatorID:            gitlab.Int(r.user.ID),
+			IssuesEnabled:        gitlab.Bool(true),
+			MergeRequestsEnabled: gitlab.Bool(true),
+			WikiEnabled:          gitlab.Bool(opt.WikiEnabled),
+			SnippetsEnabled:      gitlab.Bool(opt.SnippetsEnabled),
+			Archived:             gitlab.Bool(false),
+			CreatedAt:            &now,
+			LastActivityAt:       &now,
+			TagList:              &[]string{},
+		},
+		id:       id,
+		notes:    make(map[string][]*gitlab.Note),
+		branches: make(map[string]*gitlab.Branch),
+		commits:  make(map[string]*gitlab.Commit),
+		comments: make(map[string][]*gitlab.CommitComment),
+	}
+	s.projects = append(s.projects, p)
+
+	// Every project starts with a master branch holding an initial commit,
+	// so branches and merge requests can be created right away.
+	s.commit(p, "master", r.user, "Initial commit")
+
+	return &p.Project, nil
+}
+
+func (s *Server) editProject(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(gitlab.EditProjectOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Name != "" {
+		p.Name = gitlab.String(opt.Name)
+	}
+	if opt.Description != "" {
+		p.Description = gitlab.String(opt.Description)
+	}
+	if opt.DefaultBranch != "" {
+		if p.branches[opt.DefaultBranch] == nil {
+			return nil, errInvalid("default_branch", "does not exist")
+		}
+		p.DefaultBranch = gitlab.String(opt.DefaultBranch)
+	}
+	if opt.Visibility != "" {
+		p.Visibility = &opt.Visibility
+		p.Public = gitlab.Bool(opt.Visibility == gitlab.VisibilityPublic)
+	}
+
+	return &p.Project, nil
+}
+
+func (s *Server) deleteProject(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range s.projects {
+		if s.projects[i] == p {
+			s.projects = append(s.projects[:i], s.projects[i+1:]...)
+			break
+		}
+	}
+
+	return noContent{}, nil
+}
+
+func (s *Server) hookParam(r *request) (*project, *gitlab.ProjectHook, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := r.intParam("hook_id")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, h := range p.hooks {
+		if h.ID == id {
+			return p, h, nil
+		}
+	}
+	return nil, nil, errNotFound("Hook")
+}
+
+func (s *Server) listProjectHooks(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*gitlab.ProjectHook{}, p.hooks...), nil
+}
+
+func (s *Server) getProjectHook(r *request) (interface{}, error) {
+	_, h, err := s.hookParam(r)
+	return h, err
+}
+
+func (s *Server) addProjectHook(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(gitlab.AddProjectHookOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.URL == "" {
+		return nil, errInvalid("url", "can't be blank")
+	}
+
+	h := &gitlab.ProjectHook{
+		ID:        s.nextID(),
+		ProjectID: p.id,
+		CreatedAt: s.now(),
+	}
+	setHook(h, (*gitlab.EditProjectHookOptions)(opt))
+	p.hooks = append(p.hooks, h)
+
+	return h, nil
+}
+
+func (s *Server) editProjectHook(r *request) (interface{}, error) {
+	_, h, err := s.hookParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(gitlab.EditProjectHookOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.URL == "" {
+		opt.URL = h.URL
+	}
+	setHook(h, opt)
+
+	return h, nil
+}
+
+func setHook(h *gitlab.ProjectHook, opt *gitlab.EditProjectHookOptions) {
+	h.URL = opt.URL
+	h.PushEvents = opt.PushEvents
+	h.IssuesEvents = opt.IssuesEvents
+	h.MergeRequestsEvents = opt.MergeRequestsEvents
+	h.TagPushEvents = opt.TagPushEvents
+	h.BuildEvents = opt.BuildEvents || opt.JobEvents
+	h.JobEvents = opt.BuildEvents || opt.JobEvents
+}
+
+func (s *Server) deleteProjectHook(r *request) (interface{}, error) {
+	p, h, err := s.hookParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range p.hooks {
+		if p.hooks[i] == h {
+			p.hooks = append(p.hooks[:i], p.hooks[i+1:]...)
+			break
+		}
+	}
+
+	return noContent{}, nil
+}
@@ -0,0 +1,362 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlabtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+// Mode tells whether a Recorder records or replays requests.
+type Mode int
+
+// List of available recorder modes
+const (
+	// Replay serves the recorded responses, and fails requests which were
+	// not recorded.
+	Replay Mode = iota
+
+	// Record sends the requests to GitLab and records them, along with
+	// their responses.
+	Record
+)
+
+// Redacted replaces the secrets scrubbed from the recorded requests and
+// responses.
+const Redacted = gitlab.Redacted
+
+// Recorder is an http.RoundTripper which records the requests sent to GitLab
+// and their responses to a fixture file, and replays them later, so tests
+// can run against real payloads without network access:
+//
+//	rec, err := gitlabtest.NewRecorder("testdata/projects.json", gitlabtest.Replay)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer func() {
+//		if err := rec.Save(); err != nil {
+//			t.Error(err)
+//		}
+//	}()
+//
+//	git := gitlab.NewClient(rec.Client(), os.Getenv("GITLAB_TOKEN"))
+//
+// Credentials are scrubbed from the fixtures: the values of the
+// authentication headers, wherever they appear, and the tokens, passwords and
+// secrets held by URLs and bodies (e.g. the password sent to get a session, or
+// the private token it returns), as they are redacted from logs.
+type Recorder struct {
+	// Transport used to send requests in Record mode. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	mode Mode
+	path string
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// Interaction is a recorded request along with its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request. The URL holds the path and query
+// only, so fixtures can be replayed against any host.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// NewRecorder returns a Recorder using the fixture file at path. In Replay
+// mode the fixtures are loaded from the file, in Record mode they are written
+// to it by Save.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{mode: mode, path: path}
+	if mode == Record {
+		return r, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("gitlabtest: invalid fixtures in %s: %v", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+
+	return r, nil
+}
+
+// Client returns an HTTP client sending its requests through r.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	// Requests are scrubbed before being matched in Replay mode too, as the
+	// fixtures hold no credentials.
+	creds := secrets(req)
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    requestURI(req),
+		Header: cloneHeader(req.Header),
+		Body:   string(body),
+	}
+	scrubRequest(&recorded, creds)
+
+	if r.mode == Replay {
+		return r.replay(req, &recorded)
+	}
+	return r.record(req, &recorded, creds)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Identical requests are answered in the order they were recorded.
+	for i, in := range r.interactions {
+		if r.used[i] || !in.Request.matches(recorded) {
+			continue
+		}
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        cloneHeader(in.Response.Header),
+			Body:          ioutil.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("gitlabtest: no recorded response for %s %s in %s", recorded.Method, recorded.URL, r.path)
+}
+
+func (r *Recorder) record(
+	req *http.Request,
+	recorded *RecordedRequest,
+	secrets []string) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	in := &Interaction{
+		Request: *recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     cloneHeader(resp.Header),
+			Body:       string(body),
+		},
+	}
+	scrubResponse(&in.Response, secrets)
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the fixture file, in Record mode.
+// In Replay mode it returns an error if some of the fixtures were not used,
+// which usually means the code under test changed.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == Replay {
+		for i, used := range r.used {
+			if !used {
+				in := r.interactions[i]
+				return fmt.Errorf("gitlabtest: unused recorded request %s %s in %s", in.Request.Method, in.Request.URL, r.path)
+			}
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// matches reports whether the recorded request r matches req. Bodies holding
+// JSON are compared by value.
+func (r *RecordedRequest) matches(req *RecordedRequest) bool {
+	if r.Method != req.Method || r.URL != req.URL {
+		return false
+	}
+	if r.Body == req.Body {
+		return true
+	}
+
+	var a, b interface{}
+	if json.Unmarshal([]byte(r.Body), &a) != nil || json.Unmarshal([]byte(req.Body), &b) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return bytes.Equal(ja, jb)
+}
+
+// readBody reads the body of req, and replaces it with a copy.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// requestURI returns the escaped path and query of req.
+func requestURI(req *http.Request) string {
+	path := req.URL.Opaque
+	if strings.HasPrefix(path, "//") {
+		// Opaque URLs may hold the host, e.g. "//gitlab.com/api/v4/user".
+		if i := strings.Index(path[2:], "/"); i >= 0 {
+			path = path[2+i:]
+		}
+	}
+	if path == "" {
+		path = req.URL.EscapedPath()
+	}
+	if req.URL.RawQuery != "" {
+		path += "?" + req.URL.RawQuery
+	}
+	return path
+}
+
+func cloneHeader(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
+
+// secrets returns the credentials sent with req.
+func secrets(req *http.Request) []string {
+	var s []string
+	for _, k := range gitlab.SecretHeaders {
+		v := req.Header.Get(k)
+		if k == "Authorization" {
+			if i := strings.IndexByte(v, ' '); i >= 0 {
+				v = v[i+1:]
+			}
+		}
+		if v != "" {
+			s = append(s, v)
+		}
+	}
+	return s
+}
+
+// scrubString removes the given secrets and the credentials redacted from
+// logs by gitlab.Redact from s.
+func scrubString(s string, secrets []string) string {
+	for _, secret := range secrets {
+		s = strings.Replace(s, secret, Redacted, -1)
+	}
+	return gitlab.Redact(s)
+}
+
+// scrubRequest removes the given secrets and other credentials from req.
+func scrubRequest(req *RecordedRequest, secrets []string) {
+	req.URL = scrubString(req.URL, secrets)
+	req.Body = scrubString(req.Body, secrets)
+	scrubHeader(req.Header, secrets)
+}
+
+// scrubResponse removes the given secrets and other credentials from resp.
+func scrubResponse(resp *RecordedResponse, secrets []string) {
+	resp.Body = scrubString(resp.Body, secrets)
+	scrubHeader(resp.Header, secrets)
+}
+
+// scrubHeader removes the given secrets from h, and the values of the headers
+// holding credentials.
+func scrubHeader(h http.Header, secrets []string) {
+	for _, values := range h {
+		for i := range values {
+			values[i] = scrubString(values[i], secrets)
+		}
+	}
+	for _, k := range gitlab.SecretHeaders {
+		if h.Get(k) != "" {
+			h.Set(k, Redacted)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package gitlabtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	fixtures := filepath.Join(t.TempDir(), "testdata", "issues.json")
+
+	// Record the calls made against the fake server.
+	rec, err := NewRecorder(fixtures, Record)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	git := gitlab.NewClient(rec.Client(), RootToken)
+	git.SetBaseURL(srv.URL())
+
+	project, _, err := git.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: "demo"})
+	if err != nil {
+		t.Fatalf("Projects.CreateProject returned error: %v", err)
+	}
+	if _, _, err := git.Issues.CreateIssue(project, &gitlab.CreateIssueOptions{Title: "bug"}); err != nil {
+		t.Fatalf("Issues.CreateIssue returned error: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Recorder.Save returned error: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(fixtures)
+	if err != nil {
+		t.Fatalf("Error reading fixtures: %v", err)
+	}
+	if strings.Contains(string(data), RootToken) {
+		t.Errorf("Fixtures hold the token:\n%s", data)
+	}
+
+	// Replay them against a closed server, with another token.
+	srv.Close()
+
+	rec, err = NewRecorder(fixtures, Replay)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	git = gitlab.NewClient(rec.Client(), "another-token")
+	git.SetBaseURL(srv.URL())
+
+	if _, _, err := git.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: "demo"}); err != nil {
+		t.Fatalf("Projects.CreateProject returned error: %v", err)
+	}
+	if err := rec.Save(); err == nil {
+		t.Errorf("Recorder.Save returned no error, want an unused request error")
+	}
+
+	issue, _, err := git.Issues.CreateIssue(project, &gitlab.CreateIssueOptions{Title: "bug"})
+	if err != nil {
+		t.Fatalf("Issues.CreateIssue returned error: %v", err)
+	}
+	if issue.Title != "bug" {
+		t.Errorf("Issues.CreateIssue returned %+v", issue)
+	}
+	if err := rec.Save(); err != nil {
+		t.Errorf("Recorder.Save returned error: %v", err)
+	}
+
+	// Requests which were not recorded fail.
+	if _, _, err := git.Issues.CreateIssue(project, &gitlab.CreateIssueOptions{Title: "other"}); err == nil {
+		t.Errorf("Issues.CreateIssue returned no error for a request which was not recorded")
+	}
+}
+
+func TestScrub(t *testing.T) {
+	req := &RecordedRequest{
+		URL:    "/api/v4/session?private_token=secret&login=root",
+		Body:   `{"password":"pass"}`,
+		Header: map[string][]string{"Authorization": {"Bearer abc"}, "X-Echo": {"abc"}},
+	}
+	scrubRequest(req, []string{"abc"})
+
+	if req.URL != "/api/v4/session?private_token=[REDACTED]&login=root" {
+		t.Errorf("Scrubbed URL is %s", req.URL)
+	}
+	if req.Body != `{"password":"[REDACTED]"}` {
+		t.Errorf("Scrubbed body is %s", req.Body)
+	}
+	if req.Header.Get("Authorization") != Redacted || req.Header.Get("X-Echo") != Redacted {
+		t.Errorf("Scrubbed headers are %v", req.Header)
+	}
+
+	resp := &RecordedResponse{Body: `{"id":1,"private_token": "dd34asd13as"}`}
+	scrubResponse(resp, nil)
+
+	if resp.Body != `{"id":1,"private_token": "[REDACTED]"}` {
+		t.Errorf("Scrubbed body is %s", resp.Body)
+	}
+}
+
+func TestRecorder_session(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"username":"root","private_token":"dd34asd13as"}`)
+	}))
+	defer srv.Close()
+
+	fixtures := filepath.Join(t.TempDir(), "session.json")
+	rec, err := NewRecorder(fixtures, Record)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	git := gitlab.NewClient(rec.Client(), "")
+	git.SetBaseURL(srv.URL)
+
+	opt := &gitlab.GetSessionOptions{Login: "root", Password: `hunter"2`}
+	if _, _, err := git.Session.GetSession(opt); err != nil {
+		t.Fatalf("Session.GetSession returned error: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Recorder.Save returned error: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(fixtures)
+	if err != nil {
+		t.Fatalf("Error reading fixtures: %v", err)
+	}
+	for _, secret := range []string{"hunter", "dd34asd13as"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Fixtures hold %q:\n%s", secret, data)
+		}
+	}
+}
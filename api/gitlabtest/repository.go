@@ -0,0 +1,276 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlabtest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+// AddCommit adds a commit by root to the given branch of the project with the
+// given ID or path with namespace, as if it was pushed. A missing branch is
+// created from the default branch of the project.
+func (s *Server) AddCommit(pid, branch, message string) (*gitlab.Commit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.findProject(pid)
+	if p == nil {
+		return nil, errNotFound("Project")
+	}
+
+	c := *s.commit(p, branch, s.tokens[RootToken], message)
+	return &c, nil
+}
+
+// commit adds a commit by u on top of branch, and returns it. A missing
+// branch is created from the default branch.
+func (s *Server) commit(p *project, branch string, u *gitlab.User, message string) *gitlab.Commit {
+	b := p.branches[branch]
+	if b == nil {
+		b = &gitlab.Branch{Name: branch}
+		if head := p.branches[*p.DefaultBranch]; head != nil {
+			b.Commit = head.Commit
+		}
+		p.branches[branch] = b
+	}
+
+	var parents []string
+	if b.Commit != nil {
+		parents = []string{b.Commit.ID}
+	}
+
+	sum := sha1.Sum([]byte(fmt.Sprintf("%d\n%s\n%v\n%s", s.nextID(), branch, parents, message)))
+	id := hex.EncodeToString(sum[:])
+	now := s.now()
+
+	c := &gitlab.Commit{
+		ID:            id,
+		ShortID:       id[:8],
+		Title:         strings.SplitN(message, "\n", 2)[0],
+		Message:       message,
+		AuthorName:    u.Name,
+		AuthorEmail:   u.Email,
+		AuthoredDate:  now,
+		CommittedDate: now,
+		CreatedAt:     now,
+		ParentsIds:    parents,
+	}
+	p.commits[id] = c
+	b.Commit = c
+
+	return c
+}
+
+// resolve returns the commit a branch name or SHA points at.
+func (p *project) resolve(ref string) *gitlab.Commit {
+	if b := p.branches[ref]; b != nil {
+		return b.Commit
+	}
+	return p.commits[ref]
+}
+
+func (s *Server) branchParam(r *request) (*project, *gitlab.Branch, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := p.branches[r.params["branch"]]
+	if b == nil {
+		return nil, nil, errNotFound("Branch")
+	}
+	return p, b, nil
+}
+
+func (s *Server) listBranches(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := []*gitlab.Branch{}
+	for _, b := range p.branches {
+		branches = append(branches, b)
+	}
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+
+	return branches, nil
+}
+
+func (s *Server) getBranch(r *request) (interface{}, error) {
+	_, b, err := s.branchParam(r)
+	return b, err
+}
+
+func (s *Server) createBranch(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(gitlab.CreateBranchOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	name := opt.Branch
+	if name == "" {
+		name = opt.BranchName
+	}
+	switch {
+	case name == "":
+		return nil, errBadRequest("branch is missing")
+	case p.branches[name] != nil:
+		return nil, errBadRequest("Branch already exists")
+	}
+
+	c := p.resolve(opt.Ref)
+	if c == nil {
+		return nil, errBadRequest("Invalid reference name")
+	}
+
+	b := &gitlab.Branch{Name: name, Commit: c}
+	p.branches[name] = b
+
+	return b, nil
+}
+
+func (s *Server) deleteBranch(r *request) (interface{}, error) {
+	p, b, err := s.branchParam(r)
+	if err != nil {
+		return nil, err
+	}
+	if b.Protected || b.Name == *p.DefaultBranch {
+		return nil, errForbidden()
+	}
+
+	delete(p.branches, b.Name)
+
+	return noContent{}, nil
+}
+
+func (s *Server) protectBranch(r *request) (interface{}, error) {
+	_, b, err := s.branchParam(r)
+	if err != nil {
+		return nil, err
+	}
+	b.Protected = true
+	return b, nil
+}
+
+func (s *Server) unprotectBranch(r *request) (interface{}, error) {
+	_, b, err := s.branchParam(r)
+	if err != nil {
+		return nil, err
+	}
+	b.Protected = false
+	return b, nil
+}
+
+func (s *Server) commitParam(r *request) (*project, *gitlab.Commit, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := p.resolve(r.params["sha"])
+	if c == nil {
+		return nil, nil, errNotFound("Commit")
+	}
+	return p, c, nil
+}
+
+// listCommits returns the history of a ref, newest first.
+func (s *Server) listCommits(r *request) (interface{}, error) {
+	p, err := s.projectParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := r.URL.Query().Get("ref_name")
+	if ref == "" {
+		ref = *p.DefaultBranch
+	}
+	head := p.resolve(ref)
+	if head == nil {
+		return nil, errNotFound("Commit")
+	}
+
+	commits := []*gitlab.Commit{}
+	seen := map[string]bool{head.ID: true}
+	for queue := []*gitlab.Commit{head}; len(queue) > 0; queue = queue[1:] {
+		c := queue[0]
+		commits = append(commits, c)
+		for _, id := range c.ParentsIds {
+			if parent := p.commits[id]; parent != nil && !seen[id] {
+				seen[id] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	return commits, nil
+}
+
+func (s *Server) getCommit(r *request) (interface{}, error) {
+	_, c, err := s.commitParam(r)
+	return c, err
+}
+
+func (s *Server) listCommitComments(r *request) (interface{}, error) {
+	p, c, err := s.commitParam(r)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*gitlab.CommitComment{}, p.comments[c.ID]...), nil
+}
+
+func (s *Server) postCommitComment(r *request) (interface{}, error) {
+	p, c, err := s.commitParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := new(gitlab.PostCommitCommentOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Note == "" {
+		return nil, errInvalid("note", "can't be blank")
+	}
+
+	cc := &gitlab.CommitComment{
+		Note:     opt.Note,
+		Path:     opt.Path,
+		Line:     opt.Line,
+		LineType: opt.LineType,
+		Author: gitlab.Author{
+			ID:        r.user.ID,
+			Username:  r.user.Username,
+			Email:     r.user.Email,
+			Name:      r.user.Name,
+			State:     r.user.State,
+			CreatedAt: r.user.CreatedAt,
+		},
+	}
+	p.comments[c.ID] = append(p.comments[c.ID], cc)
+
+	return cc, nil
+}
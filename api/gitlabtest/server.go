@@ -0,0 +1,444 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package gitlabtest provides a fake GitLab server which keeps its state in
+// memory, so code using the gitlab package can be tested end to end without
+// a GitLab instance:
+//
+//	srv := gitlabtest.NewServer()
+//	defer srv.Close()
+//
+//	git := srv.Client()
+//	project, _, err := git.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: "demo"})
+//	issue, _, err := git.Issues.CreateIssue(*project.ID, &gitlab.CreateIssueOptions{Title: "bug"})
+//	issues, _, err := git.Issues.ListProjectIssues(*project.ID, nil) // holds the new issue
+//
+// The server talks API v4 and covers users, projects, issues, merge requests,
+// notes, branches, commits and project hooks. Requests to other endpoints get
+// a 404 Not Found response.
+package gitlabtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+// RootToken is the private token of the admin user root, which exists on every
+// server.
+const RootToken = "root-token"
+
+// Server is a fake GitLab server. It is safe for concurrent use.
+type Server struct {
+	// Now returns the time used for the timestamps of new objects. Defaults
+	// to time.Now.
+	Now func() time.Time
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	lastID   int
+	users    []*gitlab.User
+	tokens   map[string]*gitlab.User
+	projects []*project
+}
+
+// NewServer starts and returns a new Server, holding only the admin user root.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		Now:    time.Now,
+		tokens: make(map[string]*gitlab.User),
+	}
+
+	root := s.addUser("root", "Administrator")
+	root.IsAdmin = true
+	s.tokens[RootToken] = root
+
+	s.srv = httptest.NewServer(s)
+
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// URL returns the base URL of the API of the server.
+func (s *Server) URL() string {
+	return s.srv.URL + "/api/v4/"
+}
+
+// Client returns a new client of the server, authenticated as root.
+func (s *Server) Client() *gitlab.Client {
+	return s.NewClient(RootToken)
+}
+
+// NewClient returns a new client of the server, authenticated with the given
+// private token.
+func (s *Server) NewClient(token string) *gitlab.Client {
+	c := gitlab.NewClient(s.srv.Client(), token)
+	if err := c.SetBaseURL(s.URL()); err != nil {
+		// should never happen since the URL of the server is valid
+		panic(err)
+	}
+	return c
+}
+
+// AddUser adds a user, and returns it along with a private token
+// authenticating as the user.
+func (s *Server) AddUser(username, name string) (*gitlab.User, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.addUser(username, name)
+	token := fmt.Sprintf("token-%d", u.ID)
+	s.tokens[token] = u
+
+	user := *u
+	return &user, token
+}
+
+func (s *Server) addUser(username, name string) *gitlab.User {
+	u := &gitlab.User{
+		ID:               s.nextID(),
+		Username:         username,
+		Name:             name,
+		Email:            username + "@example.com",
+		State:            "active",
+		CreatedAt:        s.now(),
+		CanCreateGroup:   true,
+		CanCreateProject: true,
+		ProjectsLimit:    100000,
+	}
+	s.users = append(s.users, u)
+	return u
+}
+
+// now returns the current time of the server.
+func (s *Server) now() gitlab.Time {
+	return gitlab.Time{Time: s.Now()}
+}
+
+// nextID returns a new ID, unique among all objects of the server.
+func (s *Server) nextID() int {
+	s.lastID++
+	return s.lastID
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req := &request{Request: r, user: s.authenticate(r)}
+	if req.user == nil {
+		writeError(w, &apiError{http.StatusUnauthorized, "401 Unauthorized"})
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/")
+	if path == r.URL.EscapedPath() {
+		writeError(w, errNotFound(""))
+		return
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segments {
+		segments[i], _ = url.PathUnescape(seg)
+	}
+
+	for _, rt := range routes {
+		params, ok := rt.match(r.Method, segments)
+		if !ok {
+			continue
+		}
+		req.params = params
+
+		v, err := rt.handle(s, req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeResponse(w, r, v)
+		return
+	}
+
+	writeError(w, errNotFound(""))
+}
+
+// authenticate returns the user authenticated by r, if any.
+func (s *Server) authenticate(r *http.Request) *gitlab.User {
+	token := r.Header.Get("PRIVATE-TOKEN")
+	if token == "" {
+		token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	return s.tokens[token]
+}
+
+// A route maps the requests matching a method and a path pattern, in which
+// segments starting with ":" are parameters, to a handler.
+type route struct {
+	method  string
+	pattern string
+	handle  func(s *Server, r *request) (interface{}, error)
+}
+
+func (rt *route) match(method string, segments []string) (map[string]string, bool) {
+	if method != rt.method {
+		return nil, false
+	}
+
+	pattern := strings.Split(rt.pattern, "/")
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, p := range pattern {
+		switch {
+		case strings.HasPrefix(p, ":"):
+			params[p[1:]] = segments[i]
+		case p != segments[i]:
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// request is an API request being served.
+type request struct {
+	*http.Request
+	user   *gitlab.User
+	params map[string]string
+}
+
+// decode decodes the JSON body of r into v.
+func (r *request) decode(v interface{}) error {
+	if r.Body == nil || r.ContentLength == 0 {
+		return nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errBadRequest(err.Error())
+	}
+	return nil
+}
+
+// intParam returns the path parameter name as an int.
+func (r *request) intParam(name string) (int, error) {
+	v, err := strconv.Atoi(r.params[name])
+	if err != nil {
+		return 0, errNotFound("")
+	}
+	return v, nil
+}
+
+// apiError is an error response of the API.
+type apiError struct {
+	status  int
+	message interface{}
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d %v", e.status, e.message)
+}
+
+// errNotFound returns the error of a missing object, e.g. "Project".
+func errNotFound(what string) error {
+	if what == "" {
+		return &apiError{http.StatusNotFound, "404 Not Found"}
+	}
+	return &apiError{http.StatusNotFound, "404 " + what + " Not Found"}
+}
+
+func errBadRequest(message string) error {
+	return &apiError{http.StatusBadRequest, message}
+}
+
+// errInvalid returns the validation error of a field, e.g. "title" "can't be
+// blank".
+func errInvalid(field, message string) error {
+	return &apiError{http.StatusBadRequest, map[string][]string{field: {message}}}
+}
+
+func errForbidden() error {
+	return &apiError{http.StatusForbidden, "403 Forbidden"}
+}
+
+func errNotAllowed() error {
+	return &apiError{http.StatusMethodNotAllowed, "405 Method Not Allowed"}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*apiError)
+	if !ok {
+		e = &apiError{http.StatusInternalServerError, err.Error()}
+	}
+	writeJSON(w, e.status, map[string]interface{}{"message": e.message})
+}
+
+// noContent is returned by handlers to send a 204 No Content response.
+type noContent struct{}
+
+// writeResponse writes v as the response to r. Slices are paginated.
+func writeResponse(w http.ResponseWriter, r *http.Request, v interface{}) {
+	if _, ok := v.(noContent); ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	status := http.StatusOK
+	if r.Method == "POST" {
+		status = http.StatusCreated
+	}
+	if reflect.ValueOf(v).Kind() == reflect.Slice {
+		v = paginate(w, r, v)
+	}
+
+	writeJSON(w, status, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// paginate returns the page of the slice v requested by r, and sets the
+// pagination headers of the response.
+func paginate(w http.ResponseWriter, r *http.Request, v interface{}) interface{} {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage < 1 {
+		perPage = 20
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	items := reflect.ValueOf(v)
+	total := items.Len()
+	pages := int(math.Ceil(float64(total) / float64(perPage)))
+	if pages < 1 {
+		pages = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	link := func(page int, rel string) string {
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(perPage))
+		return fmt.Sprintf(`<http://%s%s?%s>; rel="%s"`, r.Host, r.URL.EscapedPath(), q.Encode(), rel)
+	}
+
+	h := w.Header()
+	h.Set("X-Page", strconv.Itoa(page))
+	h.Set("X-Per-Page", strconv.Itoa(perPage))
+	h.Set("X-Total", strconv.Itoa(total))
+	h.Set("X-Total-Pages", strconv.Itoa(pages))
+
+	var links []string
+	if page > 1 {
+		h.Set("X-Prev-Page", strconv.Itoa(page-1))
+		links = append(links, link(page-1, "prev"))
+	}
+	if page < pages {
+		h.Set("X-Next-Page", strconv.Itoa(page+1))
+		links = append(links, link(page+1, "next"))
+	}
+	links = append(links, link(1, "first"), link(pages, "last"))
+	h.Set("Link", strings.Join(links, ", "))
+
+	return items.Slice(start, end).Interface()
+}
+
+// routes are the endpoints served by the server.
+var routes = []route{
+	{"GET", "user", (*Server).currentUser},
+	{"GET", "users", (*Server).listUsers},
+	{"POST", "users", (*Server).createUser},
+	{"GET", "users/:user_id", (*Server).getUser},
+	{"PUT", "users/:user_id", (*Server).modifyUser},
+	{"DELETE", "users/:user_id", (*Server).deleteUser},
+	{"PUT", "users/:user_id/block", (*Server).blockUser},
+	{"PUT", "users/:user_id/unblock", (*Server).unblockUser},
+
+	{"GET", "projects", (*Server).listProjects},
+	{"POST", "projects", (*Server).createProject},
+	{"GET", "projects/:id", (*Server).getProject},
+	{"PUT", "projects/:id", (*Server).editProject},
+	{"DELETE", "projects/:id", (*Server).deleteProject},
+
+	{"GET", "projects/:id/hooks", (*Server).listProjectHooks},
+	{"POST", "projects/:id/hooks", (*Server).addProjectHook},
+	{"GET", "projects/:id/hooks/:hook_id", (*Server).getProjectHook},
+	{"PUT", "projects/:id/hooks/:hook_id", (*Server).editProjectHook},
+	{"DELETE", "projects/:id/hooks/:hook_id", (*Server).deleteProjectHook},
+
+	{"GET", "issues", (*Server).listIssues},
+	{"GET", "projects/:id/issues", (*Server).listProjectIssues},
+	{"POST", "projects/:id/issues", (*Server).createIssue},
+	{"GET", "projects/:id/issues/:iid", (*Server).getIssue},
+	{"PUT", "projects/:id/issues/:iid", (*Server).updateIssue},
+	{"GET", "projects/:id/issues/:iid/notes", (*Server).listIssueNotes},
+	{"POST", "projects/:id/issues/:iid/notes", (*Server).createIssueNote},
+	{"GET", "projects/:id/issues/:iid/notes/:note_id", (*Server).getIssueNote},
+	{"PUT", "projects/:id/issues/:iid/notes/:note_id", (*Server).updateIssueNote},
+
+	{"GET", "projects/:id/merge_requests", (*Server).listMergeRequests},
+	{"POST", "projects/:id/merge_requests", (*Server).createMergeRequest},
+	{"GET", "projects/:id/merge_requests/:iid", (*Server).getMergeRequest},
+	{"PUT", "projects/:id/merge_requests/:iid", (*Server).updateMergeRequest},
+	{"PUT", "projects/:id/merge_requests/:iid/merge", (*Server).acceptMergeRequest},
+	{"GET", "projects/:id/merge_requests/:iid/notes", (*Server).listMergeRequestNotes},
+	{"POST", "projects/:id/merge_requests/:iid/notes", (*Server).createMergeRequestNote},
+	{"GET", "projects/:id/merge_requests/:iid/notes/:note_id", (*Server).getMergeRequestNote},
+	{"PUT", "projects/:id/merge_requests/:iid/notes/:note_id", (*Server).updateMergeRequestNote},
+
+	{"GET", "projects/:id/repository/branches", (*Server).listBranches},
+	{"POST", "projects/:id/repository/branches", (*Server).createBranch},
+	{"GET", "projects/:id/repository/branches/:branch", (*Server).getBranch},
+	{"DELETE", "projects/:id/repository/branches/:branch", (*Server).deleteBranch},
+	{"PUT", "projects/:id/repository/branches/:branch/protect", (*Server).protectBranch},
+	{"PUT", "projects/:id/repository/branches/:branch/unprotect", (*Server).unprotectBranch},
+
+	{"GET", "projects/:id/repository/commits", (*Server).listCommits},
+	{"GET", "projects/:id/repository/commits/:sha", (*Server).getCommit},
+	{"GET", "projects/:id/repository/commits/:sha/comments", (*Server).listCommitComments},
+	{"POST", "projects/:id/repository/commits/:sha/comments", (*Server).postCommitComment},
+}
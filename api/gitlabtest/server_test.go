@@ -0,0 +1,174 @@
+package gitlabtest
+
+import (
+	"errors"
+	"testing"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+func TestIssues(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	git := srv.Client()
+
+	project, _, err := git.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: "Demo"})
+	if err != nil {
+		t.Fatalf("Projects.CreateProject returned error: %v", err)
+	}
+	if *project.PathWithNamespace != "root/demo" {
+		t.Errorf("Project path is %s, want root/demo", *project.PathWithNamespace)
+	}
+
+	opt := &gitlab.CreateIssueOptions{Title: "Crash on start", Labels: []string{"bug", "p1"}}
+	issue, _, err := git.Issues.CreateIssue(gitlab.ProjectPath("root/demo"), opt)
+	if err != nil {
+		t.Fatalf("Issues.CreateIssue returned error: %v", err)
+	}
+
+	issues, _, err := git.Issues.ListProjectIssues(project, &gitlab.ListProjectIssuesOptions{Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("Issues.ListProjectIssues returned error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != issue.ID || issues[0].Author.Username != "root" {
+		t.Fatalf("Issues.ListProjectIssues returned %v, want the new issue", issues)
+	}
+
+	_, _, err = git.Issues.UpdateIssue(project, issue.IID, &gitlab.UpdateIssueOptions{StateEvent: "close"})
+	if err != nil {
+		t.Fatalf("Issues.UpdateIssue returned error: %v", err)
+	}
+	issues, _, err = git.Issues.ListProjectIssues(project, &gitlab.ListProjectIssuesOptions{State: "opened"})
+	if err != nil || len(issues) != 0 {
+		t.Errorf("Issues.ListProjectIssues returned %v, %v, want no opened issues", issues, err)
+	}
+
+	_, _, err = git.Notes.CreateIssueNote(project, issue.IID, &gitlab.CreateIssueNoteOptions{Body: "Fixed"})
+	if err != nil {
+		t.Fatalf("Notes.CreateIssueNote returned error: %v", err)
+	}
+	notes, _, err := git.Notes.ListIssueNotes(project, issue.IID, nil)
+	if err != nil || len(notes) != 1 || notes[0].Body != "Fixed" {
+		t.Errorf("Notes.ListIssueNotes returned %v, %v, want the new note", notes, err)
+	}
+
+	_, _, err = git.Issues.GetIssue(project, 42)
+	if !errors.Is(err, gitlab.ErrNotFound) {
+		t.Errorf("Issues.GetIssue returned %v, want %v", err, gitlab.ErrNotFound)
+	}
+}
+
+func TestMergeRequests(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	git := srv.Client()
+
+	project, _, err := git.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: "demo"})
+	if err != nil {
+		t.Fatalf("Projects.CreateProject returned error: %v", err)
+	}
+	if _, err := srv.AddCommit("root/demo", "feature", "Add feature"); err != nil {
+		t.Fatalf("AddCommit returned error: %v", err)
+	}
+
+	mr, _, err := git.MergeRequests.CreateMergeRequest(project, &gitlab.CreateMergeRequestOptions{
+		Title:        "Add feature",
+		SourceBranch: "feature",
+		TargetBranch: "master",
+	})
+	if err != nil {
+		t.Fatalf("MergeRequests.CreateMergeRequest returned error: %v", err)
+	}
+
+	_, _, err = git.MergeRequests.PostMergeRequestComment(project, mr.IID, &gitlab.PostMergeRequestCommentOptions{Note: "LGTM"})
+	if err != nil {
+		t.Fatalf("MergeRequests.PostMergeRequestComment returned error: %v", err)
+	}
+
+	mr, _, err = git.MergeRequests.AcceptMergeRequest(project, mr.IID)
+	if err != nil {
+		t.Fatalf("MergeRequests.AcceptMergeRequest returned error: %v", err)
+	}
+	if mr.State != "merged" {
+		t.Errorf("Merge request state is %s, want merged", mr.State)
+	}
+
+	// The merge commit is on master, along with the commit of the feature.
+	commits, _, err := git.Commits.ListCommits(project, &gitlab.ListCommitsOptions{RefName: "master"})
+	if err != nil {
+		t.Fatalf("Commits.ListCommits returned error: %v", err)
+	}
+	if len(commits) != 3 || len(commits[0].ParentsIds) != 2 {
+		t.Errorf("Commits.ListCommits returned %v, want a merge commit and 2 others", commits)
+	}
+
+	_, _, err = git.MergeRequests.AcceptMergeRequest(project, mr.IID)
+	if !errors.Is(err, gitlab.ErrConflict) {
+		t.Errorf("MergeRequests.AcceptMergeRequest returned %v, want %v", err, gitlab.ErrConflict)
+	}
+}
+
+func TestBranchesAndHooks(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	git := srv.Client()
+
+	project, _, err := git.Projects.CreateProject(&gitlab.CreateProjectOptions{Name: "demo"})
+	if err != nil {
+		t.Fatalf("Projects.CreateProject returned error: %v", err)
+	}
+
+	_, _, err = git.Branches.CreateBranch(project, &gitlab.CreateBranchOptions{BranchName: "develop", Ref: "master"})
+	if err != nil {
+		t.Fatalf("Branches.CreateBranch returned error: %v", err)
+	}
+	branches, _, err := git.Branches.ListBranches(project, nil)
+	if err != nil || len(branches) != 2 || branches[0].Name != "develop" {
+		t.Errorf("Branches.ListBranches returned %v, %v, want develop and master", branches, err)
+	}
+
+	hook, _, err := git.Projects.AddProjectHook(project, &gitlab.AddProjectHookOptions{URL: "http://bot/hook", PushEvents: true})
+	if err != nil {
+		t.Fatalf("Projects.AddProjectHook returned error: %v", err)
+	}
+	if _, err := git.Projects.DeleteProjectHook(project, hook.ID); err != nil {
+		t.Fatalf("Projects.DeleteProjectHook returned error: %v", err)
+	}
+	hooks, _, err := git.Projects.ListProjectHooks(project, nil)
+	if err != nil || len(hooks) != 0 {
+		t.Errorf("Projects.ListProjectHooks returned %v, %v, want no hooks", hooks, err)
+	}
+}
+
+func TestUsersAndPagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		srv.AddUser(name, name)
+	}
+
+	_, token := srv.AddUser("dave", "Dave")
+	user, _, err := srv.NewClient(token).Users.CurrentUser()
+	if err != nil || user.Username != "dave" {
+		t.Fatalf("Users.CurrentUser returned %v, %v, want dave", user, err)
+	}
+
+	_, _, err = srv.NewClient("invalid").Users.CurrentUser()
+	if !errors.Is(err, gitlab.ErrUnauthorized) {
+		t.Errorf("Users.CurrentUser returned %v, want %v", err, gitlab.ErrUnauthorized)
+	}
+
+	git := srv.Client()
+	users, resp, err := git.Users.ListUsers(&gitlab.ListUsersOptions{ListOptions: gitlab.ListOptions{PerPage: 2}})
+	if err != nil {
+		t.Fatalf("Users.ListUsers returned error: %v", err)
+	}
+	if len(users) != 2 || resp.NextPage != 2 || resp.LastPage != 3 {
+		t.Errorf("Users.ListUsers returned %d users, next page %d and last page %d, want 2, 2 and 3",
+			len(users), resp.NextPage, resp.LastPage)
+	}
+}
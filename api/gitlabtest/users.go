@@ -0,0 +1,203 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlabtest
+
+import (
+	"strings"
+
+	gitlab "github.com/integram-org/gitlab/api"
+)
+
+func (s *Server) findUser(id int) *gitlab.User {
+	for _, u := range s.users {
+		if u.ID == id {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) userParam(r *request) (*gitlab.User, error) {
+	id, err := r.intParam("user_id")
+	if err != nil {
+		return nil, err
+	}
+	u := s.findUser(id)
+	if u == nil {
+		return nil, errNotFound("User")
+	}
+	return u, nil
+}
+
+func (s *Server) currentUser(r *request) (interface{}, error) {
+	return r.user, nil
+}
+
+func (s *Server) listUsers(r *request) (interface{}, error) {
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	active := r.URL.Query().Get("active") == "true"
+
+	users := []*gitlab.User{}
+	for _, u := range s.users {
+		if active && u.State != "active" {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(u.Username), search) &&
+			!strings.Contains(strings.ToLower(u.Name), search) &&
+			!strings.Contains(strings.ToLower(u.Email), search) {
+			continue
+		}
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+func (s *Server) getUser(r *request) (interface{}, error) {
+	return s.userParam(r)
+}
+
+func (s *Server) createUser(r *request) (interface{}, error) {
+	if !r.user.IsAdmin {
+		return nil, errForbidden()
+	}
+
+	opt := new(gitlab.CreateUserOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	switch {
+	case opt.Username == "":
+		return nil, errInvalid("username", "can't be blank")
+	case opt.Name == "":
+		return nil, errInvalid("name", "can't be blank")
+	}
+	for _, u := range s.users {
+		if u.Username == opt.Username {
+			return nil, errInvalid("username", "has already been taken")
+		}
+	}
+
+	u := s.addUser(opt.Username, opt.Name)
+	if opt.Email != "" {
+		u.Email = opt.Email
+	}
+	u.Bio = opt.Bio
+	u.Skype = opt.Skype
+	u.Linkedin = opt.Linkedin
+	u.Twitter = opt.Twitter
+	u.WebsiteURL = opt.WebsiteURL
+	u.IsAdmin = opt.Admin
+	u.CanCreateGroup = opt.CanCreateGroup
+	if opt.ProjectsLimit > 0 {
+		u.ProjectsLimit = opt.ProjectsLimit
+	}
+
+	return u, nil
+}
+
+func (s *Server) modifyUser(r *request) (interface{}, error) {
+	u, err := s.userParam(r)
+	if err != nil {
+		return nil, err
+	}
+	if !r.user.IsAdmin && r.user.ID != u.ID {
+		return nil, errForbidden()
+	}
+
+	opt := new(gitlab.ModifyUserOptions)
+	if err := r.decode(opt); err != nil {
+		return nil, err
+	}
+	if opt.Username != "" {
+		u.Username = opt.Username
+	}
+	if opt.Name != "" {
+		u.Name = opt.Name
+	}
+	if opt.Email != "" {
+		u.Email = opt.Email
+	}
+	if opt.Bio != "" {
+		u.Bio = opt.Bio
+	}
+	if opt.Skype != "" {
+		u.Skype = opt.Skype
+	}
+	if opt.Linkedin != "" {
+		u.Linkedin = opt.Linkedin
+	}
+	if opt.Twitter != "" {
+		u.Twitter = opt.Twitter
+	}
+	if opt.WebsiteURL != "" {
+		u.WebsiteURL = opt.WebsiteURL
+	}
+	if opt.ProjectsLimit > 0 {
+		u.ProjectsLimit = opt.ProjectsLimit
+	}
+	if r.user.IsAdmin {
+		u.IsAdmin = opt.Admin
+	}
+
+	return u, nil
+}
+
+func (s *Server) deleteUser(r *request) (interface{}, error) {
+	if !r.user.IsAdmin {
+		return nil, errForbidden()
+	}
+	u, err := s.userParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range s.users {
+		if s.users[i] == u {
+			s.users = append(s.users[:i], s.users[i+1:]...)
+			break
+		}
+	}
+	for token, tu := range s.tokens {
+		if tu == u {
+			delete(s.tokens, token)
+		}
+	}
+
+	return noContent{}, nil
+}
+
+func (s *Server) blockUser(r *request) (interface{}, error) {
+	return s.setUserState(r, "blocked")
+}
+
+func (s *Server) unblockUser(r *request) (interface{}, error) {
+	return s.setUserState(r, "active")
+}
+
+func (s *Server) setUserState(r *request, state string) (interface{}, error) {
+	if !r.user.IsAdmin {
+		return nil, errForbidden()
+	}
+	u, err := s.userParam(r)
+	if err != nil {
+		return nil, err
+	}
+	u.State = state
+	return u, nil
+}
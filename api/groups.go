@@ -18,7 +18,7 @@ package gitlab
 
 import (
 	"fmt"
-	"time"
+	"io"
 )
 
 // GroupsService handles communication with the group related methods of
@@ -37,6 +37,7 @@ type Group struct {
 	Name        string     `json:"name"`
 	Path        string     `json:"path"`
 	Description string     `json:"description"`
+	AvatarURL   string     `json:"avatar_url"`
 	Projects    *[]Project `json:"projects,omitempty"`
 }
 
@@ -52,8 +53,10 @@ type ListGroupsOptions struct {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/groups.html#list-project-groups
-func (s *GroupsService) ListGroups(opt *ListGroupsOptions) ([]*Group, *Response, error) {
-	req, err := s.client.NewRequest("GET", "groups", opt)
+func (s *GroupsService) ListGroups(
+	opt *ListGroupsOptions,
+	options ...OptionFunc) ([]*Group, *Response, error) {
+	req, err := s.client.NewRequest("GET", "groups", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -70,14 +73,16 @@ func (s *GroupsService) ListGroups(opt *ListGroupsOptions) ([]*Group, *Response,
 // GetGroup gets all details of a group.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/groups.html#details-of-a-group
-func (s *GroupsService) GetGroup(gid interface{}) (*Group, *Response, error) {
+func (s *GroupsService) GetGroup(
+	gid interface{},
+	options ...OptionFunc) (*Group, *Response, error) {
 	group, err := parseID(gid)
 	if err != nil {
 		return nil, nil, err
 	}
 	u := fmt.Sprintf("groups/%s", group)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -104,8 +109,10 @@ type CreateGroupOptions struct {
 // create groups.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/groups.html#new-group
-func (s *GroupsService) CreateGroup(opt *CreateGroupOptions) (*Group, *Response, error) {
-	req, err := s.client.NewRequest("POST", "groups", opt)
+func (s *GroupsService) CreateGroup(
+	opt *CreateGroupOptions,
+	options ...OptionFunc) (*Group, *Response, error) {
+	req, err := s.client.NewRequest("POST", "groups", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -124,14 +131,17 @@ func (s *GroupsService) CreateGroup(opt *CreateGroupOptions) (*Group, *Response,
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/groups.html#transfer-project-to-group
-func (s *GroupsService) TransferGroup(gid interface{}, project int) (*Group, *Response, error) {
+func (s *GroupsService) TransferGroup(
+	gid interface{},
+	project int,
+	options ...OptionFunc) (*Group, *Response, error) {
 	group, err := parseID(gid)
 	if err != nil {
 		return nil, nil, err
 	}
 	u := fmt.Sprintf("groups/%s/projects/%d", group, project)
 
-	req, err := s.client.NewRequest("POST", u, nil)
+	req, err := s.client.NewRequest("POST", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -148,14 +158,14 @@ func (s *GroupsService) TransferGroup(gid interface{}, project int) (*Group, *Re
 // DeleteGroup removes group with all projects inside.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/groups.html#remove-group
-func (s *GroupsService) DeleteGroup(gid interface{}) (*Response, error) {
+func (s *GroupsService) DeleteGroup(gid interface{}, options ...OptionFunc) (*Response, error) {
 	group, err := parseID(gid)
 	if err != nil {
 		return nil, err
 	}
 	u := fmt.Sprintf("groups/%s", group)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -171,13 +181,15 @@ func (s *GroupsService) DeleteGroup(gid interface{}) (*Response, error) {
 // SearchGroup get all groups that match your string in their name or path.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/groups.html#search-for-group
-func (s *GroupsService) SearchGroup(query string) ([]*Group, *Response, error) {
+func (s *GroupsService) SearchGroup(
+	query string,
+	options ...OptionFunc) ([]*Group, *Response, error) {
 	var q struct {
 		Search string `url:"search,omitempty" json:"search,omitempty"`
 	}
 	q.Search = query
 
-	req, err := s.client.NewRequest("GET", "groups", &q)
+	req, err := s.client.NewRequest("GET", "groups", &q, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -195,13 +207,22 @@ func (s *GroupsService) SearchGroup(query string) ([]*Group, *Response, error) {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/groups.html
 type GroupMember struct {
-	ID          int       `json:"id"`
-	Username    string    `json:"username"`
-	Email       string    `json:"email"`
-	Name        string    `json:"name"`
-	State       string    `json:"state"`
-	CreatedAt   time.Time `json:"created_at"`
-	AccessLevel int       `json:"access_level"`
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	CreatedAt   Time   `json:"created_at"`
+	AccessLevel int    `json:"access_level"`
+}
+
+// ListGroupMembersOptions represents the available ListGroupMembers()
+// options.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/groups.html#list-group-members
+type ListGroupMembersOptions struct {
+	ListOptions
 }
 
 // ListGroupMembers get a list of group members viewable by the authenticated
@@ -209,14 +230,17 @@ type GroupMember struct {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/groups.html#list-group-members
-func (s *GroupsService) ListGroupMembers(gid interface{}) ([]*GroupMember, *Response, error) {
+func (s *GroupsService) ListGroupMembers(
+	gid interface{},
+	opt *ListGroupMembersOptions,
+	options ...OptionFunc) ([]*GroupMember, *Response, error) {
 	group, err := parseID(gid)
 	if err != nil {
 		return nil, nil, err
 	}
 	u := fmt.Sprintf("groups/%s/members", group)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -244,14 +268,15 @@ type AddGroupMemberOptions struct {
 // http://doc.gitlab.com/ce/api/groups.html#list-group-members
 func (s *GroupsService) AddGroupMember(
 	gid interface{},
-	opt *AddGroupMemberOptions) (*GroupMember, *Response, error) {
+	opt *AddGroupMemberOptions,
+	options ...OptionFunc) (*GroupMember, *Response, error) {
 	group, err := parseID(gid)
 	if err != nil {
 		return nil, nil, err
 	}
 	u := fmt.Sprintf("groups/%s/members", group)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -281,14 +306,15 @@ type UpdateGroupMemberOptions struct {
 func (s *GroupsService) UpdateGroupMember(
 	gid interface{},
 	user int,
-	opt *UpdateGroupMemberOptions) (*GroupMember, *Response, error) {
+	opt *UpdateGroupMemberOptions,
+	options ...OptionFunc) (*GroupMember, *Response, error) {
 	group, err := parseID(gid)
 	if err != nil {
 		return nil, nil, err
 	}
 	u := fmt.Sprintf("groups/%s/members/%d", group, user)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -306,14 +332,17 @@ func (s *GroupsService) UpdateGroupMember(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/groups.html#remove-user-from-user-team
-func (s *GroupsService) RemoveGroupMember(gid interface{}, user int) (*Response, error) {
+func (s *GroupsService) RemoveGroupMember(
+	gid interface{},
+	user int,
+	options ...OptionFunc) (*Response, error) {
 	group, err := parseID(gid)
 	if err != nil {
 		return nil, err
 	}
 	u := fmt.Sprintf("groups/%s/members/%d", group, user)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -325,3 +354,32 @@ func (s *GroupsService) RemoveGroupMember(gid interface{}, user int) (*Response,
 
 	return resp, err
 }
+
+// UploadAvatar uploads an avatar for a group.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/groups.html#update-group
+func (s *GroupsService) UploadAvatar(
+	gid interface{},
+	avatar io.Reader,
+	filename string,
+	options ...OptionFunc) (*Group, *Response, error) {
+	group, err := parseID(gid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("groups/%s", group)
+
+	files := []FormFile{{Field: "avatar", Name: filename, Content: avatar}}
+	req, err := s.client.NewMultipartRequest("PUT", u, nil, files, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g := new(Group)
+	resp, err := s.client.Do(req, g)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return g, resp, err
+}
@@ -19,13 +19,12 @@ package gitlab
 import (
 	"fmt"
 	"log"
-	"net/url"
 	"strings"
-	"time"
 )
 
 // IssuesService handles communication with the issue related methods
-// of the GitLab API.
+// of the GitLab API. Single issues are identified by their ID when talking
+// to API v3, and by their IID when talking to API v4.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/issues.html
 type IssuesService struct {
@@ -43,33 +42,33 @@ type Issue struct {
 	Description string   `json:"description"`
 	Labels      []string `json:"labels"`
 	Milestone   struct {
-		ID          int       `json:"id"`
-		Title       string    `json:"title"`
-		Description string    `json:"description"`
-		DueDate     string    `json:"due_date"`
-		State       string    `json:"state"`
-		UpdatedAt   time.Time `json:"updated_at"`
-		CreatedAt   time.Time `json:"created_at"`
+		ID          int    `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		DueDate     *Date  `json:"due_date"`
+		State       string `json:"state"`
+		UpdatedAt   Time   `json:"updated_at"`
+		CreatedAt   Time   `json:"created_at"`
 	} `json:"milestone"`
 	Assignee struct {
-		ID        int       `json:"id"`
-		Username  string    `json:"username"`
-		Email     string    `json:"email"`
-		Name      string    `json:"name"`
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"created_at"`
+		ID        int    `json:"id"`
+		Username  string `json:"username"`
+		Email     string `json:"email"`
+		Name      string `json:"name"`
+		State     string `json:"state"`
+		CreatedAt Time   `json:"created_at"`
 	} `json:"assignee"`
 	Author struct {
-		ID        int       `json:"id"`
-		Username  string    `json:"username"`
-		Email     string    `json:"email"`
-		Name      string    `json:"name"`
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"created_at"`
+		ID        int    `json:"id"`
+		Username  string `json:"username"`
+		Email     string `json:"email"`
+		Name      string `json:"name"`
+		State     string `json:"state"`
+		CreatedAt Time   `json:"created_at"`
 	} `json:"author"`
-	State     string    `json:"state"`
-	UpdatedAt time.Time `json:"updated_at"`
-	CreatedAt time.Time `json:"created_at"`
+	State     string `json:"state"`
+	UpdatedAt Time   `json:"updated_at"`
+	CreatedAt Time   `json:"created_at"`
 }
 
 func (i Issue) String() string {
@@ -91,8 +90,10 @@ type ListIssuesOptions struct {
 // takes pagination parameters page and per_page to restrict the list of issues.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/issues.html#list-issues
-func (s *IssuesService) ListIssues(opt *ListIssuesOptions) ([]*Issue, *Response, error) {
-	req, err := s.client.NewRequest("GET", "issues", opt)
+func (s *IssuesService) ListIssues(
+	opt *ListIssuesOptions,
+	options ...OptionFunc) ([]*Issue, *Response, error) {
+	req, err := s.client.NewRequest("GET", "issues", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -124,15 +125,16 @@ type ListProjectIssuesOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/issues.html#list-project-issues
 func (s *IssuesService) ListProjectIssues(
-	pid interface{},
-	opt *ListProjectIssuesOptions) ([]*Issue, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ListProjectIssuesOptions,
+	options ...OptionFunc) ([]*Issue, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/issues", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/issues", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -149,14 +151,17 @@ func (s *IssuesService) ListProjectIssues(
 // GetIssue gets a single project issue.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/issues.html#single-issues
-func (s *IssuesService) GetIssue(pid interface{}, issue int) (*Issue, *Response, error) {
-	project, err := parseID(pid)
+func (s *IssuesService) GetIssue(
+	pid ProjectRef,
+	issue int,
+	options ...OptionFunc) (*Issue, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/issues/%d", url.QueryEscape(project), issue)
+	u := fmt.Sprintf("projects/%s/issues/%d", project, issue)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -185,18 +190,19 @@ type CreateIssueOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/issues.html#new-issues
 func (s *IssuesService) CreateIssue(
-	pid interface{},
-	opt *CreateIssueOptions) (*Issue, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CreateIssueOptions,
+	options ...OptionFunc) (*Issue, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/issues", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/issues", project)
 
 	// This is needed to get a single, comma separated string
 	opt.Labels = []string{strings.Join(opt.Labels, ",")}
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -229,19 +235,20 @@ type UpdateIssueOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/issues.html#edit-issues
 func (s *IssuesService) UpdateIssue(
-	pid interface{},
+	pid ProjectRef,
 	issue int,
-	opt *UpdateIssueOptions) (*Issue, *Response, error) {
-	project, err := parseID(pid)
+	opt *UpdateIssueOptions,
+	options ...OptionFunc) (*Issue, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/issues/%d", url.QueryEscape(project), issue)
+	u := fmt.Sprintf("projects/%s/issues/%d", project, issue)
 
 	// This is needed to get a single, comma separated string
 	opt.Labels = []string{strings.Join(opt.Labels, ",")}
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,423 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// JobsService handles communication with the job related methods of the
+// GitLab API. Jobs are called builds by API v3.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/jobs.html
+type JobsService struct {
+	client *Client
+}
+
+// Job represents a GitLab CI job.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/jobs.html
+type Job struct {
+	ID            int        `json:"id"`
+	Name          string     `json:"name"`
+	Stage         string     `json:"stage"`
+	Status        BuildState `json:"status"`
+	Ref           string     `json:"ref"`
+	Tag           bool       `json:"tag"`
+	AllowFailure  bool       `json:"allow_failure"`
+	Coverage      float64    `json:"coverage"`
+	Duration      float64    `json:"duration"`
+	CreatedAt     *Time      `json:"created_at"`
+	StartedAt     *Time      `json:"started_at"`
+	FinishedAt    *Time      `json:"finished_at"`
+	User          *User      `json:"user"`
+	Commit        *Commit    `json:"commit"`
+	ArtifactsFile struct {
+		Filename string `json:"filename"`
+		Size     int    `json:"size"`
+	} `json:"artifacts_file"`
+	Pipeline struct {
+		ID     int        `json:"id"`
+		Ref    string     `json:"ref"`
+		SHA    string     `json:"sha"`
+		Status BuildState `json:"status"`
+	} `json:"pipeline"`
+	Runner struct {
+		ID          int    `json:"id"`
+		Description string `json:"description"`
+		Active      bool   `json:"active"`
+		IsShared    bool   `json:"is_shared"`
+		Name        string `json:"name"`
+	} `json:"runner"`
+	WebURL string `json:"web_url"`
+}
+
+func (j Job) String() string {
+	return Stringify(j)
+}
+
+// jobsPath returns the path segment of the jobs of a project, which API v3
+// calls builds.
+func (s *JobsService) jobsPath() string {
+	if s.client.apiVersion == APIVersion3 {
+		return "builds"
+	}
+	return "jobs"
+}
+
+// ListJobsOptions represents the available ListProjectJobs() and
+// ListPipelineJobs() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#list-project-jobs
+type ListJobsOptions struct {
+	ListOptions
+	Scope []BuildState `url:"scope[],omitempty" json:"scope,omitempty"`
+}
+
+// ListProjectJobs gets a list of the jobs of a project, newest first.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#list-project-jobs
+func (s *JobsService) ListProjectJobs(
+	pid ProjectRef,
+	opt *ListJobsOptions,
+	options ...OptionFunc) ([]*Job, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/%s", project, s.jobsPath())
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var j []*Job
+	resp, err := s.client.Do(req, &j)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return j, resp, err
+}
+
+// ListPipelineJobs gets a list of the jobs of a pipeline. Only supported by
+// API v4.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#list-pipeline-jobs
+func (s *JobsService) ListPipelineJobs(
+	pid ProjectRef,
+	pipeline int,
+	opt *ListJobsOptions,
+	options ...OptionFunc) ([]*Job, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipelines/%d/jobs", project, pipeline)
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var j []*Job
+	resp, err := s.client.Do(req, &j)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return j, resp, err
+}
+
+// GetJob gets a single job of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#get-a-single-job
+func (s *JobsService) GetJob(
+	pid ProjectRef,
+	job int,
+	options ...OptionFunc) (*Job, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/%s/%d", project, s.jobsPath(), job)
+
+	req, err := s.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	j := new(Job)
+	resp, err := s.client.Do(req, j)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return j, resp, err
+}
+
+// GetJobTrace gets the trace (log) of a job. The trace holds the ANSI escape
+// sequences the job printed.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#get-a-trace-file
+func (s *JobsService) GetJobTrace(
+	pid ProjectRef,
+	job int,
+	options ...OptionFunc) ([]byte, *Response, error) {
+	var b bytes.Buffer
+	resp, err := s.StreamJobTrace(pid, job, &b, options...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return b.Bytes(), resp, err
+}
+
+// StreamJobTrace is like GetJobTrace, but writes the trace to w as it is
+// received.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#get-a-trace-file
+func (s *JobsService) StreamJobTrace(
+	pid ProjectRef,
+	job int,
+	w io.Writer,
+	options ...OptionFunc) (*Response, error) {
+	body, resp, err := s.JobTraceReader(pid, job, options...)
+	if err != nil {
+		return resp, err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+	return resp, err
+}
+
+// JobTraceReader is like GetJobTrace, but returns the trace as a stream,
+// which the caller must close.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#get-a-trace-file
+func (s *JobsService) JobTraceReader(
+	pid ProjectRef,
+	job int,
+	options ...OptionFunc) (io.ReadCloser, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/%s/%d/trace", project, s.jobsPath(), job)
+
+	return s.stream(u, options)
+}
+
+// StreamJobArtifacts writes the artifacts archive of a job to w as it is
+// received. Archives can be large, so they are not read into memory.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#get-job-artifacts
+func (s *JobsService) StreamJobArtifacts(
+	pid ProjectRef,
+	job int,
+	w io.Writer,
+	options ...OptionFunc) (*Response, error) {
+	body, resp, err := s.JobArtifactsReader(pid, job, options...)
+	if err != nil {
+		return resp, err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+	return resp, err
+}
+
+// JobArtifactsReader returns the artifacts archive of a job as a stream,
+// which the caller must close.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#get-job-artifacts
+func (s *JobsService) JobArtifactsReader(
+	pid ProjectRef,
+	job int,
+	options ...OptionFunc) (io.ReadCloser, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/%s/%d/artifacts", project, s.jobsPath(), job)
+
+	return s.stream(u, options)
+}
+
+// GetJobArtifactFile gets a single file of the artifacts archive of a job,
+// e.g. "coverage/index.html". Only supported by API v4.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#download-a-single-artifact-file
+func (s *JobsService) GetJobArtifactFile(
+	pid ProjectRef,
+	job int,
+	path string,
+	options ...OptionFunc) ([]byte, *Response, error) {
+	var b bytes.Buffer
+	resp, err := s.StreamJobArtifactFile(pid, job, path, &b, options...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return b.Bytes(), resp, err
+}
+
+// StreamJobArtifactFile is like GetJobArtifactFile, but writes the file to w
+// as it is received.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#download-a-single-artifact-file
+func (s *JobsService) StreamJobArtifactFile(
+	pid ProjectRef,
+	job int,
+	path string,
+	w io.Writer,
+	options ...OptionFunc) (*Response, error) {
+	body, resp, err := s.JobArtifactFileReader(pid, job, path, options...)
+	if err != nil {
+		return resp, err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+	return resp, err
+}
+
+// JobArtifactFileReader is like GetJobArtifactFile, but returns the file as a
+// stream, which the caller must close.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#download-a-single-artifact-file
+func (s *JobsService) JobArtifactFileReader(
+	pid ProjectRef,
+	job int,
+	path string,
+	options ...OptionFunc) (io.ReadCloser, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The path is matched as is by GitLab, so only its segments are escaped.
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	u := fmt.Sprintf("projects/%s/jobs/%d/artifacts/%s", project, job, strings.Join(segments, "/"))
+
+	return s.stream(u, options)
+}
+
+// stream gets the raw contents at u as a stream, which the caller must close.
+func (s *JobsService) stream(u string, options []OptionFunc) (io.ReadCloser, *Response, error) {
+	req, err := s.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.doStream(req)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return resp.Body, resp, err
+}
+
+// RetryJob retries a job. The retry is a new job, which is returned.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#retry-a-job
+func (s *JobsService) RetryJob(
+	pid ProjectRef,
+	job int,
+	options ...OptionFunc) (*Job, *Response, error) {
+	return s.jobAction(pid, job, "retry", options)
+}
+
+// CancelJob cancels a pending or running job.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#cancel-a-job
+func (s *JobsService) CancelJob(
+	pid ProjectRef,
+	job int,
+	options ...OptionFunc) (*Job, *Response, error) {
+	return s.jobAction(pid, job, "cancel", options)
+}
+
+// EraseJob erases the trace and the artifacts of a job.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#erase-a-job
+func (s *JobsService) EraseJob(
+	pid ProjectRef,
+	job int,
+	options ...OptionFunc) (*Job, *Response, error) {
+	return s.jobAction(pid, job, "erase", options)
+}
+
+// PlayJob triggers a manual job.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/jobs.html#play-a-job
+func (s *JobsService) PlayJob(
+	pid ProjectRef,
+	job int,
+	options ...OptionFunc) (*Job, *Response, error) {
+	return s.jobAction(pid, job, "play", options)
+}
+
+// jobAction posts the given action on a job, and returns the resulting job.
+func (s *JobsService) jobAction(
+	pid ProjectRef,
+	job int,
+	action string,
+	options []OptionFunc) (*Job, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/%s/%d/%s", project, s.jobsPath(), job, action)
+
+	req, err := s.client.NewRequest("POST", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	j := new(Job)
+	resp, err := s.client.Do(req, j)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return j, resp, err
+}
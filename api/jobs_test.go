@@ -0,0 +1,158 @@
+package gitlab
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestListProjectJobs(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query()["scope[]"], []string{"failed", "canceled"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Request scopes: %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `[{"id":7,"name":"rspec","stage":"test","status":"failed","pipeline":{"id":6}}]`)
+	})
+
+	opt := &ListJobsOptions{Scope: []BuildState{Failed, Canceled}}
+	jobs, _, err := client.Jobs.ListProjectJobs(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("Jobs.ListProjectJobs returned error: %v", err)
+	}
+
+	if len(jobs) != 1 || jobs[0].ID != 7 || jobs[0].Status != Failed || jobs[0].Pipeline.ID != 6 {
+		t.Errorf("Jobs.ListProjectJobs returned %+v", jobs)
+	}
+}
+
+func TestListProjectJobs_v3(t *testing.T) {
+	mux, server, client := setupV3()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/builds", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":7}]`)
+	})
+
+	jobs, _, err := client.Jobs.ListProjectJobs(ProjectID(1), nil)
+	if err != nil {
+		t.Fatalf("Jobs.ListProjectJobs returned error: %v", err)
+	}
+
+	if len(jobs) != 1 || jobs[0].ID != 7 {
+		t.Errorf("Jobs.ListProjectJobs returned %+v", jobs)
+	}
+}
+
+func TestListPipelineJobs(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipelines/6/jobs", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":7},{"id":8}]`)
+	})
+
+	jobs, _, err := client.Jobs.ListPipelineJobs(ProjectID(1), 6, nil)
+	if err != nil {
+		t.Fatalf("Jobs.ListPipelineJobs returned error: %v", err)
+	}
+
+	if len(jobs) != 2 {
+		t.Errorf("Jobs.ListPipelineJobs returned %+v", jobs)
+	}
+}
+
+func TestGetJobTrace(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/jobs/7/trace", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, "$ make test\nFAIL\n")
+	})
+
+	trace, _, err := client.Jobs.GetJobTrace(ProjectID(1), 7)
+	if err != nil {
+		t.Fatalf("Jobs.GetJobTrace returned error: %v", err)
+	}
+
+	if want := "$ make test\nFAIL\n"; string(trace) != want {
+		t.Errorf("Jobs.GetJobTrace returned %q, want %q", trace, want)
+	}
+}
+
+func TestStreamJobArtifacts(t *testing.T) {
+	mux, server, client := setupV3()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/builds/7/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, "PK\x03\x04")
+	})
+
+	var b bytes.Buffer
+	if _, err := client.Jobs.StreamJobArtifacts(ProjectID(1), 7, &b); err != nil {
+		t.Fatalf("Jobs.StreamJobArtifacts returned error: %v", err)
+	}
+
+	if want := "PK\x03\x04"; b.String() != want {
+		t.Errorf("Jobs.StreamJobArtifacts wrote %q, want %q", b.String(), want)
+	}
+}
+
+func TestGetJobArtifactFile(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/jobs/7/artifacts/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testUrl(t, r, "/projects/1/jobs/7/artifacts/coverage/my%20report.html")
+		fmt.Fprint(w, "<html>")
+	})
+
+	file, _, err := client.Jobs.GetJobArtifactFile(ProjectID(1), 7, "coverage/my report.html")
+	if err != nil {
+		t.Fatalf("Jobs.GetJobArtifactFile returned error: %v", err)
+	}
+
+	if string(file) != "<html>" {
+		t.Errorf("Jobs.GetJobArtifactFile returned %q", file)
+	}
+}
+
+func TestJobActions(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	for _, action := range []string{"retry", "cancel", "erase", "play"} {
+		action := action
+		mux.HandleFunc("/projects/1/jobs/7/"+action, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			fmt.Fprintf(w, `{"id":7,"name":%q}`, action)
+		})
+	}
+
+	actions := map[string]func(ProjectRef, int, ...OptionFunc) (*Job, *Response, error){
+		"retry":  client.Jobs.RetryJob,
+		"cancel": client.Jobs.CancelJob,
+		"erase":  client.Jobs.EraseJob,
+		"play":   client.Jobs.PlayJob,
+	}
+	for action, f := range actions {
+		job, _, err := f(ProjectID(1), 7)
+		if err != nil {
+			t.Errorf("%s returned error: %v", action, err)
+			continue
+		}
+		if job.Name != action {
+			t.Errorf("%s posted %q, want %q", action, job.Name, action)
+		}
+	}
+}
@@ -18,7 +18,6 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
 )
 
 // LabelsService handles communication with the label related methods
@@ -44,14 +43,16 @@ func (l Label) String() string {
 // ListLabels gets all labels for given project.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/labels.html#list-labels
-func (s *LabelsService) ListLabels(pid interface{}) ([]*Label, *Response, error) {
-	project, err := parseID(pid)
+func (s *LabelsService) ListLabels(
+	pid ProjectRef,
+	options ...OptionFunc) ([]*Label, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/labels", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/labels", project)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -78,15 +79,16 @@ type CreateLabelOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/labels.html#create-a-new-label
 func (s *LabelsService) CreateLabel(
-	pid interface{},
-	opt *CreateLabelOptions) (*Label, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CreateLabelOptions,
+	options ...OptionFunc) (*Label, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/labels", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/labels", project)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -110,14 +112,17 @@ type DeleteLabelOptions struct {
 // DeleteLabel deletes a label given by its name.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/labels.html#delete-a-label
-func (s *LabelsService) DeleteLabel(pid interface{}, opt *DeleteLabelOptions) (*Response, error) {
-	project, err := parseID(pid)
+func (s *LabelsService) DeleteLabel(
+	pid ProjectRef,
+	opt *DeleteLabelOptions,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/labels", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/labels", project)
 
-	req, err := s.client.NewRequest("DELETE", u, opt)
+	req, err := s.client.NewRequest("DELETE", u, opt, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -144,15 +149,16 @@ type UpdateLabelOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/labels.html#edit-an-existing-label
 func (s *LabelsService) UpdateLabel(
-	pid interface{},
-	opt *UpdateLabelOptions) (*Label, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *UpdateLabelOptions,
+	options ...OptionFunc) (*Label, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/labels", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/labels", project)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
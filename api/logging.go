@@ -0,0 +1,226 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A Logger writes structured debug messages. The key-value pairs follow the
+// message, alternating keys and values, so a *slog.Logger can be used as is.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+}
+
+// The LoggerFunc type is an adapter to allow the use of ordinary functions as
+// a Logger.
+type LoggerFunc func(msg string, keyvals ...interface{})
+
+// Debug calls f(msg, keyvals...).
+func (f LoggerFunc) Debug(msg string, keyvals ...interface{}) {
+	f(msg, keyvals...)
+}
+
+// LogBodyLimit is the number of bytes of the request and response bodies
+// which are logged.
+const LogBodyLimit = 2048
+
+// Redacted replaces the credentials removed from logged requests and
+// responses, and by Redact.
+const Redacted = "[REDACTED]"
+
+// SetLogger sets the logger c writes each request it sends to, nil disables
+// logging. Every attempt is logged with its method, URL, status and duration,
+// along with the headers and the first LogBodyLimit bytes of the JSON and text
+// bodies. Credentials are redacted: authentication headers, cookies, and the
+// tokens, passwords and secrets held by URLs and bodies.
+func (c *Client) SetLogger(l Logger) {
+	c.logger = l
+}
+
+// sendLogged sends req using sendLimited, and logs it along with its
+// response if c has a logger.
+func (c *Client) sendLogged(req *http.Request) (*http.Response, error) {
+	if c.logger == nil {
+		return c.sendLimited(req)
+	}
+
+	reqBody := loggedRequestBody(req)
+
+	start := time.Now()
+	resp, err := c.sendLimited(req)
+	d := time.Since(start)
+
+	keyvals := []interface{}{
+		"method", req.Method,
+		"url", Redact(requestURL(req)),
+		"request_header", redactHeader(req.Header),
+	}
+	if reqBody != "" {
+		keyvals = append(keyvals, "request_body", reqBody)
+	}
+	if err != nil {
+		keyvals = append(keyvals, "duration", d, "error", err)
+		c.logger.Debug("gitlab: request failed", keyvals...)
+		return resp, err
+	}
+
+	keyvals = append(keyvals,
+		"status", resp.StatusCode,
+		"duration", d,
+		"response_header", redactHeader(resp.Header),
+	)
+	if body := loggedResponseBody(resp); body != "" {
+		keyvals = append(keyvals, "response_body", body)
+	}
+	c.logger.Debug("gitlab: request", keyvals...)
+
+	return resp, nil
+}
+
+// loggedRequestBody returns the redacted body of req, to be logged.
+func loggedRequestBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	if ct := req.Header.Get("Content-Type"); !loggable(ct) {
+		// Streamed bodies, like multipart uploads, have no known length.
+		if req.ContentLength <= 0 {
+			return fmt.Sprintf("<streamed %s>", ct)
+		}
+		return fmt.Sprintf("<%d bytes of %s>", req.ContentLength, ct)
+	}
+	if req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	head, _ := ioutil.ReadAll(io.LimitReader(body, LogBodyLimit+1))
+	return logged(head)
+}
+
+// loggedResponseBody returns the redacted body of resp, to be logged. Only the
+// logged part of the body is read ahead, so streamed responses stay streamed.
+func loggedResponseBody(resp *http.Response) string {
+	if resp.Body == nil || !loggable(resp.Header.Get("Content-Type")) {
+		return ""
+	}
+
+	head, _ := ioutil.ReadAll(io.LimitReader(resp.Body, LogBodyLimit+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+
+	return logged(head)
+}
+
+// logged returns the first LogBodyLimit bytes of body, redacted.
+func logged(body []byte) string {
+	truncated := len(body) > LogBodyLimit
+	if truncated {
+		body = body[:LogBodyLimit]
+	}
+	s := Redact(string(body))
+	if truncated {
+		s += "...(truncated)"
+	}
+	return s
+}
+
+// loggable reports whether bodies of the given content type are logged.
+func loggable(contentType string) bool {
+	t, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(t, "text/") ||
+		strings.HasSuffix(t, "json") ||
+		t == "application/x-www-form-urlencoded"
+}
+
+// SecretHeaders are the canonical names of the request and response headers
+// holding credentials, whose values are redacted from logs.
+var SecretHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Job-Token",
+	"Private-Token",
+	"Set-Cookie",
+}
+
+// isSecretHeader reports whether the header named k holds credentials.
+func isSecretHeader(k string) bool {
+	k = http.CanonicalHeaderKey(k)
+	for _, h := range SecretHeaders {
+		if k == h {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeader returns a copy of h without credentials.
+func redactHeader(h http.Header) http.Header {
+	r := make(http.Header, len(h))
+	for k, v := range h {
+		if isSecretHeader(k) {
+			r[k] = []string{Redacted}
+			continue
+		}
+		r[k] = make([]string, len(v))
+		for i := range v {
+			r[k][i] = Redact(v[i])
+		}
+	}
+	return r
+}
+
+const secretKey = `[a-z_]*(?:token|password|secret)[a-z_]*`
+
+var (
+	// Secret JSON fields, terminated or cut off by the truncation.
+	secretFields = regexp.MustCompile(`("` + secretKey + `"\s*:\s*)"(?:[^"\\]|\\.)*(?:"|$)`)
+
+	// Secret query or form parameters.
+	secretParams = regexp.MustCompile(`((?:^|[?&])` + secretKey + `=)[^&#\s]*`)
+
+	// Credentials of URLs, e.g. the import URL of a project.
+	userinfo = regexp.MustCompile(`(://)[^/@\s"]+@`)
+)
+
+// Redact removes the credentials from a URL or body: the tokens, passwords and
+// secrets of its query parameters and JSON fields, and the user info of the
+// URLs it holds. They are replaced by Redacted.
+func Redact(s string) string {
+	s = secretFields.ReplaceAllString(s, `${1}"`+Redacted+`"`)
+	s = secretParams.ReplaceAllString(s, "${1}"+Redacted)
+	return userinfo.ReplaceAllString(s, "${1}"+Redacted+"@")
+}
@@ -18,12 +18,11 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
-	"time"
 )
 
 // MergeRequestsService handles communication with the merge requests related
-// methods of the GitLab API.
+// methods of the GitLab API. Single merge requests are identified by their ID
+// when talking to API v3, and by their IID when talking to API v4.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/merge_requests.html
 type MergeRequestsService struct {
@@ -34,19 +33,19 @@ type MergeRequestsService struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/merge_requests.html
 type MergeRequest struct {
-	ID             int       `json:"id"`
-	IID            int       `json:"iid"`
-	ProjectID      int       `json:"project_id"`
-	Title          string    `json:"title"`
-	Description    string    `json:"description"`
-	WorkInProgress bool      `json:"work_in_progress"`
-	State          string    `json:"state"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	TargetBranch   string    `json:"target_branch"`
-	SourceBranch   string    `json:"source_branch"`
-	Upvotes        int       `json:"upvotes"`
-	Downvotes      int       `json:"downvotes"`
+	ID             int    `json:"id"`
+	IID            int    `json:"iid"`
+	ProjectID      int    `json:"project_id"`
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	WorkInProgress bool   `json:"work_in_progress"`
+	State          string `json:"state"`
+	CreatedAt      Time   `json:"created_at"`
+	UpdatedAt      Time   `json:"updated_at"`
+	TargetBranch   string `json:"target_branch"`
+	SourceBranch   string `json:"source_branch"`
+	Upvotes        int    `json:"upvotes"`
+	Downvotes      int    `json:"downvotes"`
 	Author         struct {
 		Name      string `json:"name"`
 		Username  string `json:"username"`
@@ -65,15 +64,15 @@ type MergeRequest struct {
 	TargetProjectID int      `json:"target_project_id"`
 	Labels          []string `json:"labels"`
 	Milestone       struct {
-		ID          int       `json:"id"`
-		Iid         int       `json:"iid"`
-		ProjectID   int       `json:"project_id"`
-		Title       string    `json:"title"`
-		Description string    `json:"description"`
-		State       string    `json:"state"`
-		CreatedAt   time.Time `json:"created_at"`
-		UpdatedAt   time.Time `json:"updated_at"`
-		DueDate     string    `json:"due_date"`
+		ID          int    `json:"id"`
+		Iid         int    `json:"iid"`
+		ProjectID   int    `json:"project_id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		State       string `json:"state"`
+		CreatedAt   Time   `json:"created_at"`
+		UpdatedAt   Time   `json:"updated_at"`
+		DueDate     *Date  `json:"due_date"`
 	} `json:"milestone"`
 	Files []struct {
 		OldPath     string `json:"old_path"`
@@ -112,15 +111,16 @@ type ListMergeRequestsOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/merge_requests.html#list-merge-requests
 func (s *MergeRequestsService) ListMergeRequests(
-	pid interface{},
-	opt *ListMergeRequestsOptions) ([]*MergeRequest, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ListMergeRequestsOptions,
+	options ...OptionFunc) ([]*MergeRequest, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_requests", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/merge_requests", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -139,15 +139,16 @@ func (s *MergeRequestsService) ListMergeRequests(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/merge_requests.html#get-single-mr
 func (s *MergeRequestsService) GetMergeRequest(
-	pid interface{},
-	mergeRequest int) (*MergeRequest, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	mergeRequest int,
+	options ...OptionFunc) (*MergeRequest, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_request/%d", url.QueryEscape(project), mergeRequest)
+	u := s.mergeRequestPath(project, mergeRequest)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -167,15 +168,16 @@ func (s *MergeRequestsService) GetMergeRequest(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/merge_requests.html#get-single-mr-changes
 func (s *MergeRequestsService) GetMergeRequestChanges(
-	pid interface{},
-	mergeRequest int) (*MergeRequest, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	mergeRequest int,
+	options ...OptionFunc) (*MergeRequest, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_request/%d/changes", url.QueryEscape(project), mergeRequest)
+	u := s.mergeRequestPath(project, mergeRequest) + "/changes"
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -208,15 +210,16 @@ type CreateMergeRequestOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/merge_requests.html#create-mr
 func (s *MergeRequestsService) CreateMergeRequest(
-	pid interface{},
-	opt *CreateMergeRequestOptions) (*MergeRequest, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CreateMergeRequestOptions,
+	options ...OptionFunc) (*MergeRequest, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_requests", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/merge_requests", project)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -248,16 +251,17 @@ type UpdateMergeRequestOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/merge_requests.html#update-mr
 func (s *MergeRequestsService) UpdateMergeRequest(
-	pid interface{},
+	pid ProjectRef,
 	mergeRequest int,
-	opt *UpdateMergeRequestOptions) (*MergeRequest, *Response, error) {
-	project, err := parseID(pid)
+	opt *UpdateMergeRequestOptions,
+	options ...OptionFunc) (*MergeRequest, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_request/%d", url.QueryEscape(project), mergeRequest)
+	u := s.mergeRequestPath(project, mergeRequest)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -273,21 +277,23 @@ func (s *MergeRequestsService) UpdateMergeRequest(
 
 // AcceptMergeRequest merges changes submitted with MR using this API. If merge
 // success you get 200 OK. If it has some conflicts and can not be merged - you
-// get 405 and error message 'Branch cannot be merged'. If merge request is
-// already merged or closed - you get 405 and error message 'Method Not Allowed'
+// get 405 (406 with API v4) and error message 'Branch cannot be merged'. If
+// merge request is already merged or closed - you get 405 and error message
+// 'Method Not Allowed'. In all cases the returned error matches ErrConflict.
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/merge_requests.html#accept-mr
 func (s *MergeRequestsService) AcceptMergeRequest(
-	pid interface{},
-	mergeRequest int) (*MergeRequest, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	mergeRequest int,
+	options ...OptionFunc) (*MergeRequest, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_request/%d/merge", url.QueryEscape(project), mergeRequest)
+	u := s.mergeRequestPath(project, mergeRequest) + "/merge"
 
-	req, err := s.client.NewRequest("PUT", u, nil)
+	req, err := s.client.NewRequest("PUT", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -301,18 +307,26 @@ func (s *MergeRequestsService) AcceptMergeRequest(
 	return m, resp, err
 }
 
+// mergeRequestPath returns the path of a single merge request.
+func (s *MergeRequestsService) mergeRequestPath(project string, mergeRequest int) string {
+	if s.client.apiVersion == APIVersion3 {
+		return fmt.Sprintf("projects/%s/merge_request/%d", project, mergeRequest)
+	}
+	return fmt.Sprintf("projects/%s/merge_requests/%d", project, mergeRequest)
+}
+
 // MergeRequestComment represents a GitLab merge request comment.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/merge_requests.html
 type MergeRequestComment struct {
 	Note   string `json:"note"`
 	Author struct {
-		ID        int       `json:"id"`
-		Username  string    `json:"username"`
-		Email     string    `json:"email"`
-		Name      string    `json:"name"`
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"created_at"`
+		ID        int    `json:"id"`
+		Username  string `json:"username"`
+		Email     string `json:"email"`
+		Name      string `json:"name"`
+		State     string `json:"state"`
+		CreatedAt Time   `json:"created_at"`
 	} `json:"author"`
 }
 
@@ -335,16 +349,39 @@ type GetMergeRequestCommentsOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/merge_requests.html#get-the-comments-on-a-mr
 func (s *MergeRequestsService) GetMergeRequestComments(
-	pid interface{},
+	pid ProjectRef,
 	mergeRequest int,
-	opt *GetMergeRequestCommentsOptions) ([]*MergeRequestComment, *Response, error) {
-	project, err := parseID(pid)
+	opt *GetMergeRequestCommentsOptions,
+	options ...OptionFunc) ([]*MergeRequestComment, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_request/%d/comments", url.QueryEscape(project), mergeRequest)
+	if s.client.apiVersion != APIVersion3 {
+		// API v4 dropped comments in favour of notes.
+		u := s.mergeRequestPath(project, mergeRequest) + "/notes"
+
+		req, err := s.client.NewRequest("GET", u, opt, options...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var n []*Note
+		resp, err := s.client.Do(req, &n)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		c := make([]*MergeRequestComment, len(n))
+		for i := range n {
+			c[i] = commentFromNote(n[i])
+		}
+
+		return c, resp, err
+	}
+	u := s.mergeRequestPath(project, mergeRequest) + "/comments"
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -372,16 +409,39 @@ type PostMergeRequestCommentOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/commits.html#post-comment-to-mr
 func (s *MergeRequestsService) PostMergeRequestComment(
-	pid interface{},
+	pid ProjectRef,
 	mergeRequest int,
-	opt *PostMergeRequestCommentOptions) (*MergeRequestComment, *Response, error) {
-	project, err := parseID(pid)
+	opt *PostMergeRequestCommentOptions,
+	options ...OptionFunc) (*MergeRequestComment, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_request/%d/comments", url.QueryEscape(project), mergeRequest)
+	if s.client.apiVersion != APIVersion3 {
+		// API v4 dropped comments in favour of notes.
+		u := s.mergeRequestPath(project, mergeRequest) + "/notes"
+
+		var o *CreateMergeRequestNoteOptions
+		if opt != nil {
+			o = &CreateMergeRequestNoteOptions{Body: opt.Note}
+		}
+
+		req, err := s.client.NewRequest("POST", u, o, options...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		n := new(Note)
+		resp, err := s.client.Do(req, n)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		return commentFromNote(n), resp, err
+	}
+	u := s.mergeRequestPath(project, mergeRequest) + "/comments"
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -394,3 +454,8 @@ func (s *MergeRequestsService) PostMergeRequestComment(
 
 	return c, resp, err
 }
+
+// commentFromNote converts an API v4 merge request note into a comment.
+func commentFromNote(n *Note) *MergeRequestComment {
+	return &MergeRequestComment{Note: n.Body, Author: n.Author}
+}
@@ -0,0 +1,132 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAcceptMergeRequest(t *testing.T) {
+	for _, v := range []struct {
+		version APIVersion
+		path    string
+	}{
+		{APIVersion3, "/projects/1/merge_request/42/merge"},
+		{APIVersion4, "/projects/1/merge_requests/7/merge"},
+	} {
+		mux, server, client := setup()
+		client.SetAPIVersion(v.version)
+
+		mux.HandleFunc(v.path, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "PUT")
+			fmt.Fprint(w, `{"id":42,"iid":7,"state":"merged"}`)
+		})
+
+		mr := 42
+		if v.version == APIVersion4 {
+			mr = 7
+		}
+
+		m, _, err := client.MergeRequests.AcceptMergeRequest(ProjectID(1), mr)
+		if err != nil {
+			t.Errorf("MergeRequests.AcceptMergeRequest (%s) returned error: %v", v.version, err)
+		}
+
+		if m == nil || m.ID != 42 || m.IID != 7 || m.State != "merged" {
+			t.Errorf("MergeRequests.AcceptMergeRequest (%s) returned %+v", v.version, m)
+		}
+
+		teardown(server)
+	}
+}
+
+func TestAcceptMergeRequest_notMergeable(t *testing.T) {
+	// API v3 answers 405 Method Not Allowed, and API v4 406 Not Acceptable.
+	for _, code := range []int{http.StatusMethodNotAllowed, http.StatusNotAcceptable} {
+		mux, server, client := setup()
+
+		mux.HandleFunc("/projects/1/merge_requests/1/merge", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"message":"Branch cannot be merged"}`, code)
+		})
+
+		_, _, err := client.MergeRequests.AcceptMergeRequest(ProjectID(1), 1)
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("MergeRequests.AcceptMergeRequest (%d) returned %v, want ErrConflict", code, err)
+		}
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) || errResp.Message != "Branch cannot be merged" {
+			t.Errorf("MergeRequests.AcceptMergeRequest (%d) returned %#v, want an *ErrorResponse", code, err)
+		}
+
+		teardown(server)
+	}
+}
+
+func TestGetMergeRequestComments_v3(t *testing.T) {
+	mux, server, client := setupV3()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/merge_request/42/comments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"note":"LGTM","author":{"username":"jdoe"}}]`)
+	})
+
+	comments, _, err := client.MergeRequests.GetMergeRequestComments(ProjectID(1), 42, nil)
+	if err != nil {
+		t.Errorf("MergeRequests.GetMergeRequestComments returned error: %v", err)
+	}
+
+	want := []*MergeRequestComment{{Note: "LGTM"}}
+	want[0].Author.Username = "jdoe"
+	if !reflect.DeepEqual(want, comments) {
+		t.Errorf("MergeRequests.GetMergeRequestComments returned %+v, want %+v", comments, want)
+	}
+}
+
+func TestGetMergeRequestComments_v4(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/merge_requests/7/notes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":1,"body":"LGTM","author":{"username":"jdoe"}}]`)
+	})
+
+	comments, _, err := client.MergeRequests.GetMergeRequestComments(ProjectID(1), 7, nil)
+	if err != nil {
+		t.Errorf("MergeRequests.GetMergeRequestComments returned error: %v", err)
+	}
+
+	want := []*MergeRequestComment{{Note: "LGTM"}}
+	want[0].Author.Username = "jdoe"
+	if !reflect.DeepEqual(want, comments) {
+		t.Errorf("MergeRequests.GetMergeRequestComments returned %+v, want %+v", comments, want)
+	}
+}
+
+func TestPostMergeRequestComment_v4(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/merge_requests/7/notes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testJsonBody(t, r, values{
+			"body": "LGTM",
+		})
+		fmt.Fprint(w, `{"id":1,"body":"LGTM"}`)
+	})
+
+	opt := &PostMergeRequestCommentOptions{Note: "LGTM"}
+	comment, _, err := client.MergeRequests.PostMergeRequestComment(ProjectID(1), 7, opt)
+	if err != nil {
+		t.Errorf("MergeRequests.PostMergeRequestComment returned error: %v", err)
+	}
+
+	want := &MergeRequestComment{Note: "LGTM"}
+	if !reflect.DeepEqual(want, comment) {
+		t.Errorf("MergeRequests.PostMergeRequestComment returned %+v, want %+v", comment, want)
+	}
+}
@@ -0,0 +1,317 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A MetricsCollector records the outcome of API calls. It allows the metrics
+// of a Client to be exported to any monitoring system.
+type MetricsCollector interface {
+	// ObserveRequest records a call to endpoint (see NormalizeEndpoint) which
+	// took d. status is 0 when no response was received, in which case err
+	// holds the reason.
+	ObserveRequest(method, endpoint string, status int, d time.Duration, err error)
+}
+
+// MetricsMiddleware returns a Middleware reporting every call to c.
+func MetricsMiddleware(c MetricsCollector) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			c.ObserveRequest(req.Method, Endpoint(req), status, time.Since(start), err)
+
+			return resp, err
+		}
+	}
+}
+
+// DefaultLatencyBuckets are the upper bounds of the latency histograms of
+// NewMetrics, when none are given.
+var DefaultLatencyBuckets = []time.Duration{
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Metrics is a MetricsCollector keeping per-endpoint latency histograms and
+// status code counters in memory. It implements expvar.Var, so it can be
+// published using expvar.Publish.
+type Metrics struct {
+	mu        sync.Mutex
+	buckets   []time.Duration
+	endpoints map[endpointKey]*EndpointMetrics
+}
+
+type endpointKey struct {
+	method, endpoint string
+}
+
+// EndpointMetrics holds the metrics of the calls made to a single endpoint
+// using a single method.
+type EndpointMetrics struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+
+	// Number of calls, and of calls which got no response.
+	Count  int `json:"count"`
+	Errors int `json:"errors"`
+
+	// Number of calls per status code of the response.
+	Status map[int]int `json:"status"`
+
+	// Latency histogram. Buckets[i] is the number of calls which took at
+	// most Bounds[i], calls exceeding the last bound are only counted in
+	// Count. Sum is the total latency of all calls.
+	Bounds  []time.Duration `json:"bounds"`
+	Buckets []int           `json:"buckets"`
+	Sum     time.Duration   `json:"sum"`
+}
+
+// NewMetrics returns a new Metrics with latency histograms using the given
+// bucket bounds, or DefaultLatencyBuckets if none are given.
+func NewMetrics(buckets ...time.Duration) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]time.Duration(nil), buckets...)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+
+	return &Metrics{
+		buckets:   buckets,
+		endpoints: make(map[endpointKey]*EndpointMetrics),
+	}
+}
+
+// ObserveRequest implements the MetricsCollector interface.
+func (m *Metrics) ObserveRequest(method, endpoint string, status int, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := endpointKey{method, endpoint}
+	e, ok := m.endpoints[key]
+	if !ok {
+		e = &EndpointMetrics{
+			Method:   method,
+			Endpoint: endpoint,
+			Status:   make(map[int]int),
+			Bounds:   m.buckets,
+			Buckets:  make([]int, len(m.buckets)),
+		}
+		m.endpoints[key] = e
+	}
+
+	e.Count++
+	if status == 0 {
+		e.Errors++
+	} else {
+		e.Status[status]++
+	}
+	for i, bound := range m.buckets {
+		if d <= bound {
+			e.Buckets[i]++
+		}
+	}
+	e.Sum += d
+}
+
+// Middleware returns a Middleware reporting every call to m.
+func (m *Metrics) Middleware() Middleware {
+	return MetricsMiddleware(m)
+}
+
+// Snapshot returns a copy of the metrics of all endpoints called so far,
+// sorted by endpoint and method.
+func (m *Metrics) Snapshot() []EndpointMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]EndpointMetrics, 0, len(m.endpoints))
+	for _, e := range m.endpoints {
+		c := *e
+		c.Status = make(map[int]int, len(e.Status))
+		for k, v := range e.Status {
+			c.Status[k] = v
+		}
+		c.Buckets = append([]int(nil), e.Buckets...)
+		snapshot = append(snapshot, c)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Endpoint != snapshot[j].Endpoint {
+			return snapshot[i].Endpoint < snapshot[j].Endpoint
+		}
+		return snapshot[i].Method < snapshot[j].Method
+	})
+
+	return snapshot
+}
+
+// String returns the snapshot of m as JSON, which implements expvar.Var.
+func (m *Metrics) String() string {
+	b, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "[]"
+	}
+	return string(b)
+}
+
+// A Tracer starts the spans tracing API calls. It allows the calls of a Client
+// to be reported to any tracing system, e.g. by wrapping an OpenTracing or
+// OpenTelemetry tracer.
+type Tracer interface {
+	// StartSpan starts a span named name, as a child of the span of ctx if
+	// any. The returned context holds the new span.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// A Span traces a single API call.
+type Span interface {
+	// SetTag adds a tag to the span.
+	SetTag(key string, value interface{})
+
+	// Finish ends the span. err is the error of the call, if any.
+	Finish(err error)
+}
+
+// TracingMiddleware returns a Middleware tracing every call in a span of t.
+// Spans are named after the method and endpoint of the call (e.g. "GET
+// projects/:id"), and tagged with the method, URL and status code. The URL is
+// redacted like in logs, see Redact.
+func TracingMiddleware(t Tracer) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx, span := t.StartSpan(req.Context(), req.Method+" "+Endpoint(req))
+			span.SetTag("http.method", req.Method)
+			span.SetTag("http.url", Redact(requestURL(req)))
+
+			resp, err := next(req.WithContext(ctx))
+			if resp != nil {
+				span.SetTag("http.status_code", resp.StatusCode)
+				if resp.StatusCode >= 400 {
+					span.SetTag("error", true)
+				}
+			}
+			span.Finish(err)
+
+			return resp, err
+		}
+	}
+}
+
+// SpanRecorder is a Tracer keeping the spans of the last calls in memory,
+// e.g. to inspect them in tests or in a debug page.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	max   int
+	spans []RecordedSpan
+}
+
+// RecordedSpan is a span finished by a SpanRecorder.
+type RecordedSpan struct {
+	Name   string
+	Parent string // name of the parent span, if any
+	Start  time.Time
+	End    time.Time
+	Tags   map[string]interface{}
+	Err    error
+}
+
+// Duration returns the duration of the span.
+func (s *RecordedSpan) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// NewSpanRecorder returns a SpanRecorder keeping the last max spans, or all
+// spans if max is 0.
+func NewSpanRecorder(max int) *SpanRecorder {
+	return &SpanRecorder{max: max}
+}
+
+type spanKey struct{}
+
+// StartSpan implements the Tracer interface.
+func (r *SpanRecorder) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	s := &recordingSpan{
+		recorder: r,
+		span: RecordedSpan{
+			Name:  name,
+			Start: time.Now(),
+			Tags:  make(map[string]interface{}),
+		},
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*recordingSpan); ok {
+		s.span.Parent = parent.span.Name
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Spans returns the finished spans, oldest first.
+func (r *SpanRecorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedSpan(nil), r.spans...)
+}
+
+func (r *SpanRecorder) record(s RecordedSpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, s)
+	if r.max > 0 && len(r.spans) > r.max {
+		r.spans = r.spans[len(r.spans)-r.max:]
+	}
+}
+
+type recordingSpan struct {
+	recorder *SpanRecorder
+	mu       sync.Mutex
+	span     RecordedSpan
+}
+
+func (s *recordingSpan) SetTag(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.span.Tags[key] = value
+}
+
+func (s *recordingSpan) Finish(err error) {
+	s.mu.Lock()
+	s.span.End = time.Now()
+	s.span.Err = err
+	span := s.span
+	s.mu.Unlock()
+
+	s.recorder.record(span)
+}
@@ -0,0 +1,161 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// A Handler sends an API request and returns the response of GitLab.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// A Middleware wraps the Handler sending the requests of a Client, to observe
+// or modify requests and responses. It sees each call once: retries and cache
+// revalidation happen inside the wrapped Handler.
+type Middleware func(next Handler) Handler
+
+// Use adds middleware to the chain wrapped around the requests of c. The
+// first middleware added is the outermost one, and sees requests first and
+// responses last.
+func (c *Client) Use(middleware ...Middleware) {
+	// Never share the backing array with clients returned by WithContext.
+	c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], middleware...)
+}
+
+// chain returns h wrapped in the middleware of c.
+func (c *Client) chain(h Handler) Handler {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
+
+// endpointParams maps the path segments which are followed by an identifier
+// to the placeholder replacing it.
+var endpointParams = map[string]string{
+	"blobs":          ":sha",
+	"branches":       ":branch",
+	"commits":        ":sha",
+	"files":          ":file_path",
+	"fork":           ":id",
+	"groups":         ":id",
+	"namespaces":     ":id",
+	"projects":       ":id",
+	"protected_tags": ":tag_name",
+	"raw_blobs":      ":sha",
+	"releases":       ":tag_name",
+	"search":         ":query",
+	"statuses":       ":sha",
+	"tags":           ":tag_name",
+	"users":          ":id",
+	"variables":      ":key",
+}
+
+// endpointPaths maps the path segments which are followed by a path of any
+// number of segments to the placeholder replacing it.
+var endpointPaths = map[string]string{
+	"artifacts": ":artifact_path",
+}
+
+// endpointStatics are the path segments which are never identifiers, even
+// when they follow a segment of endpointParams (e.g. projects/owned).
+var endpointStatics = map[string]bool{
+	"all":     true,
+	"fork":    true,
+	"import":  true,
+	"owned":   true,
+	"search":  true,
+	"starred": true,
+	"visible": true,
+}
+
+var (
+	apiPrefix = regexp.MustCompile(`^.*?/api/v\d+/`)
+	sha       = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// NormalizeEndpoint returns the endpoint of the given API path, with the
+// identifiers replaced by placeholders, e.g. "projects/:id/merge_requests"
+// for "projects/namespace%2Fproject/merge_requests". Endpoints have a bounded
+// number of values, which makes them usable as metric labels.
+func NormalizeEndpoint(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	path = apiPrefix.ReplaceAllString(path, "")
+	path = strings.Trim(path, "/")
+
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if i > 0 {
+			if param, ok := endpointPaths[segments[i-1]]; ok {
+				segments = append(segments[:i], param)
+				break
+			}
+		}
+		if i > 0 && !endpointStatics[s] {
+			if param, ok := endpointParams[segments[i-1]]; ok {
+				segments[i] = param
+				continue
+			}
+		}
+		if isNumber(s) {
+			segments[i] = ":id"
+		} else if sha.MatchString(s) {
+			segments[i] = ":sha"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// Endpoint returns the normalized endpoint of the API request req, see
+// NormalizeEndpoint.
+func Endpoint(req *http.Request) string {
+	if req.URL.Opaque != "" {
+		return NormalizeEndpoint(req.URL.Opaque)
+	}
+	return NormalizeEndpoint(req.URL.EscapedPath())
+}
+
+// requestURL returns the URL of req. URL.String does not render the opaque
+// URLs built by NewRequest as absolute URLs.
+func requestURL(req *http.Request) string {
+	if !strings.HasPrefix(req.URL.Opaque, "/") {
+		return req.URL.String()
+	}
+	u := req.URL.Scheme + "://" + req.URL.Host + req.URL.Opaque
+	if req.URL.RawQuery != "" {
+		u += "?" + req.URL.RawQuery
+	}
+	return u
+}
+
+// isNumber reports whether s is a non-empty string of digits.
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	for _, tt := range []struct{ path, want string }{
+		{"/api/v4/projects", "projects"},
+		{"/api/v4/projects/1/merge_requests", "projects/:id/merge_requests"},
+		{"/api/v4/projects/namespace%2Fproject/merge_requests/12/notes", "projects/:id/merge_requests/:id/notes"},
+		{"/api/v3/projects/1/merge_request/12/comments", "projects/:id/merge_request/:id/comments"},
+		{"/api/v3/projects/owned", "projects/owned"},
+		{"/api/v3/projects/search/gitlab", "projects/search/:query"},
+		{"/api/v3/projects/fork/1", "projects/fork/:id"},
+		{"/api/v4/projects/1/repository/branches/feature%2Ftest", "projects/:id/repository/branches/:branch"},
+		{"/api/v4/projects/1/repository/files/docs%2FREADME.md/raw?ref=master", "projects/:id/repository/files/:file_path/raw"},
+		{"/api/v4/projects/1/repository/commits/6104942438c14ec7bd21c6cd5bd995272b3faff6/comments", "projects/:id/repository/commits/:sha/comments"},
+		{"/api/v4/projects/1/repository/blobs/6104942438c14ec7bd21c6cd5bd995272b3faff6/raw", "projects/:id/repository/blobs/:sha/raw"},
+		{"/api/v4/projects/1/jobs/8/artifacts", "projects/:id/jobs/:id/artifacts"},
+		{"/api/v4/projects/1/jobs/8/artifacts/coverage/index.html", "projects/:id/jobs/:id/artifacts/:artifact_path"},
+		{"/api/v4/projects/1/pipeline_schedules/13/variables", "projects/:id/pipeline_schedules/:id/variables"},
+		{"/api/v4/projects/1/pipeline_schedules/13/variables/DEPLOY_ENV", "projects/:id/pipeline_schedules/:id/variables/:key"},
+		{"/api/v4/projects/1/releases/v1.0", "projects/:id/releases/:tag_name"},
+		{"/api/v4/projects/1/releases/release%2Fv1.0/assets/links/3", "projects/:id/releases/:tag_name/assets/links/:id"},
+		{"/api/v4/projects/1/protected_tags/release-%2A", "projects/:id/protected_tags/:tag_name"},
+		{"/api/v3/projects/1/repository/raw_blobs/master", "projects/:id/repository/raw_blobs/:sha"},
+		{"/api/v4/projects/1/statuses/6104942", "projects/:id/statuses/:sha"},
+		{"/api/v4/projects/1/repository/commits/6104942/statuses", "projects/:id/repository/commits/:sha/statuses"},
+		{"/api/v4/groups/my-group/projects", "groups/:id/projects"},
+		{"/gitlab/api/v4/users/jdoe", "users/:id"},
+		{"/user", "user"},
+	} {
+		if got := NormalizeEndpoint(tt.path); got != tt.want {
+			t.Errorf("NormalizeEndpoint(%q) returned %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestUse(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if got := strings.Join(r.Header["X-Middleware"], ","); got != "outer,inner" {
+			t.Errorf("X-Middleware headers are %s, want outer,inner", got)
+		}
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				req.Header.Add("X-Middleware", name)
+				resp, err := next(req)
+				calls = append(calls, name+" done")
+				return resp, err
+			}
+		}
+	}
+	client.Use(trace("outer"), trace("inner"))
+
+	// Middleware added to a derived client does not leak into c.
+	client.WithContext(client.Context()).Use(trace("derived"))
+
+	if _, _, err := client.Users.CurrentUser(); err != nil {
+		t.Fatalf("Users.CurrentUser returned error: %v", err)
+	}
+
+	want := []string{"outer", "inner", "inner done", "outer done"}
+	if !reflect.DeepEqual(want, calls) {
+		t.Errorf("Middleware calls are %v, want %v", calls, want)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/projects/2/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"404 Project Not Found"}`, http.StatusNotFound)
+	})
+
+	metrics := NewMetrics(time.Hour)
+	client.Use(metrics.Middleware())
+
+	client.MergeRequests.ListMergeRequests(ProjectID(1), nil)
+	client.MergeRequests.ListMergeRequests(ProjectID(1), nil)
+	client.MergeRequests.ListMergeRequests(ProjectID(2), nil)
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 1 {
+		t.Fatalf("Metrics has %d endpoints, want 1: %+v", len(snapshot), snapshot)
+	}
+
+	e := snapshot[0]
+	if e.Method != "GET" || e.Endpoint != "projects/:id/merge_requests" {
+		t.Errorf("Metrics endpoint is %s %s, want GET projects/:id/merge_requests", e.Method, e.Endpoint)
+	}
+	if e.Count != 3 || e.Errors != 0 || !reflect.DeepEqual(e.Buckets, []int{3}) {
+		t.Errorf("Metrics counted %+v, want 3 calls in the first bucket", e)
+	}
+	if want := map[int]int{200: 2, 404: 1}; !reflect.DeepEqual(want, e.Status) {
+		t.Errorf("Metrics status counters are %v, want %v", e.Status, want)
+	}
+	if !strings.Contains(metrics.String(), `"endpoint":"projects/:id/merge_requests"`) {
+		t.Errorf("Metrics.String returned %s", metrics.String())
+	}
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	recorder := NewSpanRecorder(0)
+	client.Use(TracingMiddleware(recorder))
+
+	ctx, parent := recorder.StartSpan(client.Context(), "webhook")
+	if _, _, err := client.WithContext(ctx).Projects.GetProject(ProjectID(1)); err != nil {
+		t.Fatalf("Projects.GetProject returned error: %v", err)
+	}
+	parent.Finish(nil)
+
+	spans := recorder.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("SpanRecorder recorded %d spans, want 2", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name != "GET projects/:id" || span.Parent != "webhook" {
+		t.Errorf("Span is %q with parent %q, want %q with parent %q", span.Name, span.Parent, "GET projects/:id", "webhook")
+	}
+	if span.Tags["http.status_code"] != 200 {
+		t.Errorf("Span tags are %v, want status code 200", span.Tags)
+	}
+	if url := span.Tags["http.url"]; url != server.URL+"/projects/1" {
+		t.Errorf("Span URL is %v, want %s", url, server.URL+"/projects/1")
+	}
+}
+
+func TestTracingMiddleware_redact(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	recorder := NewSpanRecorder(0)
+	client.Use(TracingMiddleware(recorder))
+
+	opt := struct {
+		PrivateToken string `url:"private_token"`
+	}{"secret"}
+	req, err := client.NewRequest("GET", "projects/1", opt)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	want := server.URL + "/projects/1?private_token=" + Redacted
+	if url := recorder.Spans()[0].Tags["http.url"]; url != want {
+		t.Errorf("Span URL is %v, want %s", url, want)
+	}
+}
@@ -18,8 +18,6 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
-	"time"
 )
 
 // MilestonesService handles communication with the milestone related methods
@@ -34,15 +32,15 @@ type MilestonesService struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/branches.html
 type Milestone struct {
-	ID          int       `json:"id"`
-	Iid         int       `json:"iid"`
-	ProjectID   int       `json:"project_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	DueDate     string    `json:"due_date"`
-	State       string    `json:"state"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int    `json:"id"`
+	Iid         int    `json:"iid"`
+	ProjectID   int    `json:"project_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	DueDate     *Date  `json:"due_date"`
+	State       string `json:"state"`
+	UpdatedAt   Time   `json:"updated_at"`
+	CreatedAt   Time   `json:"created_at"`
 }
 
 func (m Milestone) String() string {
@@ -63,15 +61,16 @@ type ListMilestonesOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/milestones.html#list-project-milestones
 func (s *MilestonesService) ListMilestones(
-	pid interface{},
-	opt *ListMilestonesOptions) ([]*Milestone, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ListMilestonesOptions,
+	options ...OptionFunc) ([]*Milestone, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/milestones", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/milestones", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -90,15 +89,16 @@ func (s *MilestonesService) ListMilestones(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/milestones.html#get-single-milestone
 func (s *MilestonesService) GetMilestone(
-	pid interface{},
-	milestone int) (*Milestone, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	milestone int,
+	options ...OptionFunc) (*Milestone, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/milestones/%d", url.QueryEscape(project), milestone)
+	u := fmt.Sprintf("projects/%s/milestones/%d", project, milestone)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -119,7 +119,7 @@ func (s *MilestonesService) GetMilestone(
 type CreateMilestoneOptions struct {
 	Title       string `url:"title,omitempty" json:"title,omitempty"`
 	Description string `url:"description,omitempty" json:"description,omitempty"`
-	DueDate     string `url:"due_date,omitempty" json:"due_date,omitempty"`
+	DueDate     *Date  `url:"due_date,omitempty" json:"due_date,omitempty"`
 }
 
 // CreateMilestone creates a new project milestone.
@@ -127,15 +127,16 @@ type CreateMilestoneOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/milestones.html#create-new-milestone
 func (s *MilestonesService) CreateMilestone(
-	pid interface{},
-	opt *CreateMilestoneOptions) (*Milestone, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CreateMilestoneOptions,
+	options ...OptionFunc) (*Milestone, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/milestones", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/milestones", project)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -156,7 +157,7 @@ func (s *MilestonesService) CreateMilestone(
 type UpdateMilestoneOptions struct {
 	Title       string `url:"title,omitempty" json:"title,omitempty"`
 	Description string `url:"description,omitempty" json:"description,omitempty"`
-	DueDate     string `url:"due_date,omitempty" json:"due_date,omitempty"`
+	DueDate     *Date  `url:"due_date,omitempty" json:"due_date,omitempty"`
 	StateEvent  string `url:"state_event,omitempty" json:"state_event,omitempty"`
 }
 
@@ -165,16 +166,17 @@ type UpdateMilestoneOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/milestones.html#edit-milestone
 func (s *MilestonesService) UpdateMilestone(
-	pid interface{},
+	pid ProjectRef,
 	milestone int,
-	opt *UpdateMilestoneOptions) (*Milestone, *Response, error) {
-	project, err := parseID(pid)
+	opt *UpdateMilestoneOptions,
+	options ...OptionFunc) (*Milestone, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/milestones/%d", url.QueryEscape(project), milestone)
+	u := fmt.Sprintf("projects/%s/milestones/%d", project, milestone)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -201,16 +203,17 @@ type GetMilestoneIssuesOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/milestones.html#get-all-issues-assigned-to-a-single-milestone
 func (s *MilestonesService) GetMilestoneIssues(
-	pid interface{},
+	pid ProjectRef,
 	milestone int,
-	opt *GetMilestoneIssuesOptions) ([]*Issue, *Response, error) {
-	project, err := parseID(pid)
+	opt *GetMilestoneIssuesOptions,
+	options ...OptionFunc) ([]*Issue, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/milestones/%d/issues", url.QueryEscape(project), milestone)
+	u := fmt.Sprintf("projects/%s/milestones/%d/issues", project, milestone)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
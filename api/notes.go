@@ -18,12 +18,11 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
-	"time"
 )
 
 // NotesService handles communication with the notes related methods
-// of the GitLab API.
+// of the GitLab API. Issues and merge requests are identified by their ID
+// when talking to API v3, and by their IID when talking to API v4.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/notes.html
 type NotesService struct {
@@ -40,16 +39,16 @@ type Note struct {
 	Title      string `json:"title"`
 	FileName   string `json:"file_name"`
 	Author     struct {
-		ID        int       `json:"id"`
-		Username  string    `json:"username"`
-		Email     string    `json:"email"`
-		Name      string    `json:"name"`
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"created_at"`
+		ID        int    `json:"id"`
+		Username  string `json:"username"`
+		Email     string `json:"email"`
+		Name      string `json:"name"`
+		State     string `json:"state"`
+		CreatedAt Time   `json:"created_at"`
 	} `json:"author"`
-	ExpiresAt *time.Time `json:"expires_at"`
-	UpdatedAt string     `json:"updated_at"`
-	CreatedAt string     `json:"created_at"`
+	ExpiresAt *Time `json:"expires_at"`
+	UpdatedAt Time  `json:"updated_at"`
+	CreatedAt Time  `json:"created_at"`
 }
 
 func (n Note) String() string {
@@ -69,16 +68,17 @@ type ListIssueNotesOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#list-project-issue-notes
 func (s *NotesService) ListIssueNotes(
-	pid interface{},
+	pid ProjectRef,
 	issue int,
-	opt *ListIssueNotesOptions) ([]*Note, *Response, error) {
-	project, err := parseID(pid)
+	opt *ListIssueNotesOptions,
+	options ...OptionFunc) ([]*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/issues/%d/notes", url.QueryEscape(project), issue)
+	u := fmt.Sprintf("projects/%s/issues/%d/notes", project, issue)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -97,16 +97,17 @@ func (s *NotesService) ListIssueNotes(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#get-single-issue-note
 func (s *NotesService) GetIssueNote(
-	pid interface{},
+	pid ProjectRef,
 	issue int,
-	note int) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	note int,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/issues/%d/notes/%d", url.QueryEscape(project), issue, note)
+	u := fmt.Sprintf("projects/%s/issues/%d/notes/%d", project, issue, note)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -143,16 +144,17 @@ type CreateCommitNoteOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#create-new-issue-note
 func (s *NotesService) CreateIssueNote(
-	pid interface{},
+	pid ProjectRef,
 	issue int,
-	opt *CreateIssueNoteOptions) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	opt *CreateIssueNoteOptions,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/issues/%d/notes", url.QueryEscape(project), issue)
+	u := fmt.Sprintf("projects/%s/issues/%d/notes", project, issue)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -171,16 +173,17 @@ func (s *NotesService) CreateIssueNote(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#create-new-issue-note
 func (s *NotesService) CreateCommitNote(
-	pid interface{},
+	pid ProjectRef,
 	commitID string,
-	opt *CreateCommitNoteOptions) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	opt *CreateCommitNoteOptions,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/commits/%s/comments", url.QueryEscape(project), commitID)
+	u := fmt.Sprintf("projects/%s/repository/commits/%s/comments", project, commitID)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -207,17 +210,18 @@ type UpdateIssueNoteOptions struct {
 //
 // http://doc.gitlab.com/ce/api/notes.html#modify-existing-issue-note
 func (s *NotesService) UpdateIssueNote(
-	pid interface{},
+	pid ProjectRef,
 	issue int,
 	note int,
-	opt *UpdateIssueNoteOptions) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	opt *UpdateIssueNoteOptions,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/issues/%d/notes/%d", url.QueryEscape(project), issue, note)
+	u := fmt.Sprintf("projects/%s/issues/%d/notes/%d", project, issue, note)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -231,19 +235,32 @@ func (s *NotesService) UpdateIssueNote(
 	return n, resp, err
 }
 
+// ListSnippetNotesOptions represents the available ListSnippetNotes()
+// options.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/notes.html#list-all-snippet-notes
+type ListSnippetNotesOptions struct {
+	ListOptions
+}
+
 // ListSnippetNotes gets a list of all notes for a single snippet. Snippet
 // notes are comments users can post to a snippet.
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#list-all-snippet-notes
-func (s *NotesService) ListSnippetNotes(pid interface{}, snippet int) ([]*Note, *Response, error) {
-	project, err := parseID(pid)
+func (s *NotesService) ListSnippetNotes(
+	pid ProjectRef,
+	snippet int,
+	opt *ListSnippetNotesOptions,
+	options ...OptionFunc) ([]*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets/%d/notes", url.QueryEscape(project), snippet)
+	u := fmt.Sprintf("projects/%s/snippets/%d/notes", project, snippet)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -262,16 +279,17 @@ func (s *NotesService) ListSnippetNotes(pid interface{}, snippet int) ([]*Note,
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#get-single-snippet-note
 func (s *NotesService) GetSnippetNote(
-	pid interface{},
+	pid ProjectRef,
 	snippet int,
-	note int) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	note int,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets/%d/notes/%d", url.QueryEscape(project), snippet, note)
+	u := fmt.Sprintf("projects/%s/snippets/%d/notes/%d", project, snippet, note)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -300,16 +318,17 @@ type CreateSnippetNoteOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#create-new-snippet-note
 func (s *NotesService) CreateSnippetNote(
-	pid interface{},
+	pid ProjectRef,
 	snippet int,
-	opt *CreateSnippetNoteOptions) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	opt *CreateSnippetNoteOptions,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets/%d/notes", url.QueryEscape(project), snippet)
+	u := fmt.Sprintf("projects/%s/snippets/%d/notes", project, snippet)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -336,17 +355,18 @@ type UpdateSnippetNoteOptions struct {
 //
 // http://doc.gitlab.com/ce/api/notes.html#modify-existing-snippet-note
 func (s *NotesService) UpdateSnippetNote(
-	pid interface{},
+	pid ProjectRef,
 	snippet int,
 	note int,
-	opt *UpdateSnippetNoteOptions) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	opt *UpdateSnippetNoteOptions,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets/%d/notes/%d", url.QueryEscape(project), snippet, note)
+	u := fmt.Sprintf("projects/%s/snippets/%d/notes/%d", project, snippet, note)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -360,20 +380,31 @@ func (s *NotesService) UpdateSnippetNote(
 	return n, resp, err
 }
 
+// ListMergeRequestNotesOptions represents the available
+// ListMergeRequestNotes() options.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/notes.html#list-all-merge-request-notes
+type ListMergeRequestNotesOptions struct {
+	ListOptions
+}
+
 // ListMergeRequestNotes gets a list of all notes for a single merge request.
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#list-all-merge-request-notes
 func (s *NotesService) ListMergeRequestNotes(
-	pid interface{},
-	mergeRequest int) ([]*Note, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	mergeRequest int,
+	opt *ListMergeRequestNotesOptions,
+	options ...OptionFunc) ([]*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_requests/%d/notes", url.QueryEscape(project), mergeRequest)
+	u := fmt.Sprintf("projects/%s/merge_requests/%d/notes", project, mergeRequest)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -392,16 +423,17 @@ func (s *NotesService) ListMergeRequestNotes(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#get-single-merge-request-note
 func (s *NotesService) GetMergeRequestNote(
-	pid interface{},
+	pid ProjectRef,
 	mergeRequest int,
-	note int) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	note int,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_requests/%d/notes/%d", url.QueryEscape(project), mergeRequest, note)
+	u := fmt.Sprintf("projects/%s/merge_requests/%d/notes/%d", project, mergeRequest, note)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -429,16 +461,17 @@ type CreateMergeRequestNoteOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/notes.html#create-new-merge-request-note
 func (s *NotesService) CreateMergeRequestNote(
-	pid interface{},
+	pid ProjectRef,
 	mergeRequest int,
-	opt *CreateMergeRequestNoteOptions) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	opt *CreateMergeRequestNoteOptions,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/merge_requests/%d/notes", url.QueryEscape(project), mergeRequest)
+	u := fmt.Sprintf("projects/%s/merge_requests/%d/notes", project, mergeRequest)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -465,18 +498,19 @@ type UpdateMergeRequestNoteOptions struct {
 //
 // http://doc.gitlab.com/ce/api/notes.html#modify-existing-merge-request-note
 func (s *NotesService) UpdateMergeRequestNote(
-	pid interface{},
+	pid ProjectRef,
 	mergeRequest int,
 	note int,
-	opt *UpdateMergeRequestNoteOptions) (*Note, *Response, error) {
-	project, err := parseID(pid)
+	opt *UpdateMergeRequestNoteOptions,
+	options ...OptionFunc) (*Note, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
 	u := fmt.Sprintf(
-		"projects/%s/merge_requests/%d/notes/%d", url.QueryEscape(project), mergeRequest, note)
+		"projects/%s/merge_requests/%d/notes/%d", project, mergeRequest, note)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
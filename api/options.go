@@ -0,0 +1,83 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// OptionFunc can be passed to all API methods to modify the request of a
+// single call:
+//
+//	projects, _, err := git.Projects.ListProjects(opt, gitlab.WithSudo("jdoe"))
+type OptionFunc func(*http.Request) error
+
+// WithSudo makes the call on behalf of another user, identified by ID or
+// username. Only available to admins.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/README.html#sudo
+func WithSudo(uid interface{}) OptionFunc {
+	return func(req *http.Request) error {
+		user, err := parseID(uid)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("SUDO", user)
+		return nil
+	}
+}
+
+// WithHeader sets the header name to value, replacing any existing values.
+func WithHeader(name, value string) OptionFunc {
+	return func(req *http.Request) error {
+		req.Header.Set(name, value)
+		return nil
+	}
+}
+
+// WithPerPage sets the number of results returned per page, overriding the
+// value of the ListOptions of the call.
+func WithPerPage(perPage int) OptionFunc {
+	return func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set("per_page", strconv.Itoa(perPage))
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
+
+// WithContext makes the call using ctx instead of the context of the Client.
+func WithContext(ctx context.Context) OptionFunc {
+	return func(req *http.Request) error {
+		if ctx == nil {
+			return errors.New("nil context")
+		}
+		*req = *req.WithContext(ctx)
+		return nil
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header, for proxies in front of
+// GitLab which deduplicate requests. GitLab itself ignores the header and
+// does not deduplicate requests, so it does not make a request safe to retry:
+// non-idempotent requests are only retried with RetryNonIdempotent.
+func WithIdempotencyKey(key string) OptionFunc {
+	return WithHeader("Idempotency-Key", key)
+}
@@ -0,0 +1,90 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestWithSudo(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "SUDO", "jdoe")
+		fmt.Fprint(w, `{"id":2,"username":"jdoe"}`)
+	})
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "SUDO", "2")
+		fmt.Fprint(w, `[{"id":1}]`)
+	})
+
+	user, _, err := client.Users.CurrentUser(WithSudo("jdoe"))
+	if err != nil {
+		t.Fatalf("Users.CurrentUser returned error: %v", err)
+	}
+
+	if _, _, err := client.Projects.ListProjects(nil, WithSudo(user.ID)); err != nil {
+		t.Errorf("Projects.ListProjects returned error: %v", err)
+	}
+}
+
+func TestWithSudo_invalidID(t *testing.T) {
+	_, server, client := setup()
+	defer teardown(server)
+
+	if _, _, err := client.Users.CurrentUser(WithSudo(1.5)); err == nil {
+		t.Errorf("Users.CurrentUser returned no error for an invalid sudo user")
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "X-Request-Id", "42")
+		testHeader(t, r, "Idempotency-Key", "key")
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	_, _, err := client.Users.CurrentUser(WithHeader("X-Request-Id", "42"), WithIdempotencyKey("key"))
+	if err != nil {
+		t.Errorf("Users.CurrentUser returned error: %v", err)
+	}
+}
+
+func TestWithPerPage(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{
+			"page":     "2",
+			"per_page": "100",
+		})
+		fmt.Fprint(w, `[{"id":1}]`)
+	})
+
+	opt := &ListProjectsOptions{ListOptions: ListOptions{Page: 2, PerPage: 3}}
+	if _, _, err := client.Projects.ListProjects(opt, WithPerPage(100)); err != nil {
+		t.Errorf("Projects.ListProjects returned error: %v", err)
+	}
+}
+
+func TestWithContext_option(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := client.Users.CurrentUser(WithContext(ctx)); err != context.Canceled {
+		t.Errorf("Users.CurrentUser returned error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,176 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import "reflect"
+
+// PageFunc fetches a single page of a paginated list. It is called with the
+// ListOptions of the page that should be retrieved, and is typically a small
+// closure around one of the List methods of a service:
+//
+//	func(page gitlab.ListOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+//		opt.ListOptions = page
+//		return git.Projects.ListProjects(opt)
+//	}
+type PageFunc[T any] func(page ListOptions) ([]T, *Response, error)
+
+// PaginationOptions represents the available options when walking through all
+// pages of a list.
+type PaginationOptions struct {
+	// The page to start at. Defaults to the first page.
+	Page int
+
+	// The number of items to request per page. Defaults to the GitLab
+	// default (20).
+	PerPage int
+
+	// The maximum number of items to return. Zero means no limit.
+	MaxItems int
+
+	// Use keyset pagination, see ListOptions.Pagination. Page is ignored.
+	Keyset bool
+}
+
+// A Pager lazily walks through the pages of a paginated list, following the
+// NextPage values of the responses. Pages are only requested when Next is
+// called, so a caller can stop at any point without fetching the rest:
+//
+//	p := gitlab.NewPager(fetch, nil)
+//	for p.Next() {
+//		for _, project := range p.Page() {
+//			...
+//		}
+//	}
+//	if err := p.Err(); err != nil {
+//		...
+//	}
+type Pager[T any] struct {
+	fetch PageFunc[T]
+	opt   ListOptions
+	max   int
+
+	seen int
+	page []T
+	resp *Response
+	err  error
+	done bool
+}
+
+// NewPager returns a Pager that fetches pages using fetch. If opt is nil, the
+// pager starts at the first page and does not limit the number of items.
+func NewPager[T any](fetch PageFunc[T], opt *PaginationOptions) *Pager[T] {
+	p := &Pager[T]{fetch: fetch}
+	if opt != nil {
+		p.opt = ListOptions{Page: opt.Page, PerPage: opt.PerPage}
+		p.max = opt.MaxItems
+		if opt.Keyset {
+			p.opt = ListOptions{PerPage: opt.PerPage, Pagination: "keyset"}
+		}
+	}
+	return p
+}
+
+// Next fetches the next page. It returns false when all pages have been
+// fetched, when the item limit has been reached, when the pager was stopped
+// or when an error occurred.
+func (p *Pager[T]) Next() bool {
+	if p.done {
+		return false
+	}
+	if p.max > 0 && p.seen >= p.max {
+		p.Stop()
+		return false
+	}
+
+	page, resp, err := p.fetch(p.opt)
+	p.resp = resp
+	if err != nil {
+		p.err = err
+		p.Stop()
+		return false
+	}
+
+	if p.max > 0 && p.seen+len(page) > p.max {
+		page = page[:p.max-p.seen]
+	}
+	p.page = page
+	p.seen += len(page)
+
+	// Stop when there is no next page, or when the server keeps on returning
+	// the same page (which means pagination is not supported by the endpoint).
+	switch {
+	case resp != nil && resp.NextCursor != nil:
+		if reflect.DeepEqual(resp.NextCursor, p.opt.Cursor) {
+			p.done = true
+		}
+		p.opt.Cursor = resp.NextCursor
+	case resp == nil || resp.NextPage == 0 || resp.NextPage <= p.opt.Page:
+		p.done = true
+	default:
+		p.opt.Page = resp.NextPage
+	}
+
+	return len(page) > 0 || !p.done
+}
+
+// Page returns the items of the page fetched by the last call to Next.
+func (p *Pager[T]) Page() []T {
+	return p.page
+}
+
+// Response returns the response of the last page that was fetched.
+func (p *Pager[T]) Response() *Response {
+	return p.resp
+}
+
+// Err returns the error, if any, that stopped the pager.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Stop stops the pager. Subsequent calls to Next return false.
+func (p *Pager[T]) Stop() {
+	p.done = true
+	p.page = nil
+}
+
+// CollectAll fetches all pages and returns their items in a single slice. If
+// an error occurs, the items collected so far are returned together with the
+// error.
+func CollectAll[T any](fetch PageFunc[T], opt *PaginationOptions) ([]T, error) {
+	var all []T
+	err := ForEach(fetch, opt, func(item T) bool {
+		all = append(all, item)
+		return true
+	})
+	return all, err
+}
+
+// ForEach calls f for every item of every page, fetching pages as they are
+// needed. If f returns false, ForEach stops without fetching any more pages.
+func ForEach[T any](fetch PageFunc[T], opt *PaginationOptions, f func(T) bool) error {
+	p := NewPager(fetch, opt)
+	for p.Next() {
+		for _, item := range p.Page() {
+			if !f(item) {
+				p.Stop()
+				return nil
+			}
+		}
+	}
+	return p.Err()
+}
@@ -0,0 +1,219 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// paginatedProjects registers a handler on mux which serves 3 pages of 2
+// projects each, and records the pages that were requested.
+func paginatedProjects(t *testing.T, mux *http.ServeMux, server string) *[]int {
+	var requested []int
+
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+
+		page, _ := strconv.Atoi(r.FormValue("page"))
+		if page == 0 {
+			page = 1
+		}
+		requested = append(requested, page)
+
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(
+				`<%s/projects?page=%d&per_page=2>; rel="next", <%s/projects?page=3&per_page=2>; rel="last"`,
+				server, page+1, server))
+		}
+		fmt.Fprintf(w, `[{"id":%d},{"id":%d}]`, page*2-1, page*2)
+	})
+
+	return &requested
+}
+
+func listProjects(client *Client) PageFunc[*Project] {
+	opt := &ListProjectsOptions{}
+	return func(page ListOptions) ([]*Project, *Response, error) {
+		opt.ListOptions = page
+		return client.Projects.ListProjects(opt)
+	}
+}
+
+func projectIDs(projects []*Project) []int {
+	var ids []int
+	for _, p := range projects {
+		ids = append(ids, *p.ID)
+	}
+	return ids
+}
+
+func TestCollectAll(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	requested := paginatedProjects(t, mux, server.URL)
+
+	projects, err := CollectAll(listProjects(client), &PaginationOptions{PerPage: 2})
+	if err != nil {
+		t.Fatalf("CollectAll returned error: %v", err)
+	}
+
+	if want := []int{1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(projectIDs(projects), want) {
+		t.Errorf("CollectAll returned %v, want %v", projectIDs(projects), want)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(*requested, want) {
+		t.Errorf("CollectAll requested pages %v, want %v", *requested, want)
+	}
+}
+
+func TestCollectAll_maxItems(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	requested := paginatedProjects(t, mux, server.URL)
+
+	projects, err := CollectAll(listProjects(client), &PaginationOptions{MaxItems: 3})
+	if err != nil {
+		t.Fatalf("CollectAll returned error: %v", err)
+	}
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(projectIDs(projects), want) {
+		t.Errorf("CollectAll returned %v, want %v", projectIDs(projects), want)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(*requested, want) {
+		t.Errorf("CollectAll requested pages %v, want %v", *requested, want)
+	}
+}
+
+func TestForEach_stop(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	requested := paginatedProjects(t, mux, server.URL)
+
+	var ids []int
+	err := ForEach(listProjects(client), nil, func(p *Project) bool {
+		ids = append(ids, *p.ID)
+		return *p.ID != 3
+	})
+	if err != nil {
+		t.Fatalf("ForEach returned error: %v", err)
+	}
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ForEach visited %v, want %v", ids, want)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(*requested, want) {
+		t.Errorf("ForEach requested pages %v, want %v", *requested, want)
+	}
+}
+
+func TestPager(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	paginatedProjects(t, mux, server.URL)
+
+	p := NewPager(listProjects(client), &PaginationOptions{Page: 2})
+
+	var pages [][]int
+	for p.Next() {
+		pages = append(pages, projectIDs(p.Page()))
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("Pager returned error: %v", err)
+	}
+
+	if want := [][]int{{3, 4}, {5, 6}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("Pager returned pages %v, want %v", pages, want)
+	}
+	if p.Next() {
+		t.Errorf("Pager.Next returned true after the last page")
+	}
+}
+
+func TestPager_error(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"500 Internal Server Error"}`, http.StatusInternalServerError)
+	})
+
+	p := NewPager(listProjects(client), nil)
+	if p.Next() {
+		t.Errorf("Pager.Next returned true on error")
+	}
+	if _, ok := p.Err().(*ErrorResponse); !ok {
+		t.Errorf("Pager.Err returned %v, want *ErrorResponse", p.Err())
+	}
+	if p.Response() == nil || p.Response().StatusCode != http.StatusInternalServerError {
+		t.Errorf("Pager.Response returned %v, want the failed response", p.Response())
+	}
+}
+
+func TestCollectAll_keyset(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	var cursors []string
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.FormValue("pagination") != "keyset" || r.FormValue("page") != "" {
+			t.Errorf("Request query is %s, want keyset pagination", r.URL.RawQuery)
+		}
+		if r.FormValue("order_by") != "id" {
+			t.Errorf("Request query is %s, want order_by=id", r.URL.RawQuery)
+		}
+		// The parameters of the cursor replace those of the options.
+		for key, values := range r.URL.Query() {
+			if len(values) != 1 {
+				t.Errorf("Request query is %s, want a single %s", r.URL.RawQuery, key)
+			}
+		}
+
+		after, _ := strconv.Atoi(r.FormValue("id_after"))
+		cursors = append(cursors, r.FormValue("id_after"))
+
+		if after < 4 {
+			w.Header().Set("Link", fmt.Sprintf(
+				`<%s/projects?id_after=%d&order_by=id&pagination=keyset&per_page=2&sort=asc>; rel="next"`,
+				server.URL, after+2))
+		}
+		fmt.Fprintf(w, `[{"id":%d},{"id":%d}]`, after+1, after+2)
+	})
+
+	opt := &ListProjectsOptions{OrderBy: "id", Sort: "asc"}
+	fetch := func(page ListOptions) ([]*Project, *Response, error) {
+		opt.ListOptions = page
+		return client.Projects.ListProjects(opt)
+	}
+
+	projects, err := CollectAll(fetch, &PaginationOptions{PerPage: 2, Keyset: true})
+	if err != nil {
+		t.Fatalf("CollectAll returned error: %v", err)
+	}
+
+	if want := []int{1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(projectIDs(projects), want) {
+		t.Errorf("CollectAll returned projects %v, want %v", projectIDs(projects), want)
+	}
+	if want := []string{"", "2", "4"}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("CollectAll requested cursors %v, want %v", cursors, want)
+	}
+}
+
+func TestCursor_EncodeValues(t *testing.T) {
+	v := url.Values{"order_by": {"id"}, "per_page": {"2"}, "search": {"api"}}
+	c := Cursor{"id_after": {"4"}, "order_by": {"id"}, "per_page": {"2"}}
+	if err := c.EncodeValues("cursor", &v); err != nil {
+		t.Fatalf("Cursor.EncodeValues returned error: %v", err)
+	}
+
+	want := url.Values{"id_after": {"4"}, "order_by": {"id"}, "per_page": {"2"}, "search": {"api"}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Cursor.EncodeValues encoded %v, want %v", v, want)
+	}
+}
@@ -0,0 +1,367 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// PipelineSchedulesService handles communication with the pipeline schedule
+// related methods of the GitLab API. Only supported by API v4.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/pipeline_schedules.html
+type PipelineSchedulesService struct {
+	client *Client
+}
+
+// PipelineSchedule represents a pipeline schedule of a project.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/pipeline_schedules.html
+type PipelineSchedule struct {
+	ID           int    `json:"id"`
+	Description  string `json:"description"`
+	Ref          string `json:"ref"`
+	Cron         string `json:"cron"`
+	CronTimezone string `json:"cron_timezone"`
+	NextRunAt    *Time  `json:"next_run_at"`
+	Active       bool   `json:"active"`
+	CreatedAt    *Time  `json:"created_at"`
+	UpdatedAt    *Time  `json:"updated_at"`
+	Owner        *User  `json:"owner"`
+	LastPipeline struct {
+		ID     int        `json:"id"`
+		SHA    string     `json:"sha"`
+		Ref    string     `json:"ref"`
+		Status BuildState `json:"status"`
+	} `json:"last_pipeline"`
+	Variables []*PipelineScheduleVariable `json:"variables"`
+}
+
+func (s PipelineSchedule) String() string {
+	return Stringify(s)
+}
+
+// PipelineScheduleVariable represents a variable passed to the pipelines of
+// a schedule.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#pipeline-schedule-variables
+type PipelineScheduleVariable struct {
+	Key          string `json:"key"`
+	Value        string `json:"value"`
+	VariableType string `json:"variable_type"`
+}
+
+// ListPipelineSchedulesOptions represents the available
+// ListPipelineSchedules() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#get-all-pipeline-schedules
+type ListPipelineSchedulesOptions struct {
+	ListOptions
+	Scope string `url:"scope,omitempty" json:"scope,omitempty"`
+}
+
+// ListPipelineSchedules gets a list of the pipeline schedules of a project.
+// The scope can be "active" or "inactive".
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#get-all-pipeline-schedules
+func (s *PipelineSchedulesService) ListPipelineSchedules(
+	pid ProjectRef,
+	opt *ListPipelineSchedulesOptions,
+	options ...OptionFunc) ([]*PipelineSchedule, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipeline_schedules", project)
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ps []*PipelineSchedule
+	resp, err := s.client.Do(req, &ps)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ps, resp, err
+}
+
+// GetPipelineSchedule gets a single pipeline schedule of a project, along
+// with its variables.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#get-a-single-pipeline-schedule
+func (s *PipelineSchedulesService) GetPipelineSchedule(
+	pid ProjectRef,
+	schedule int,
+	options ...OptionFunc) (*PipelineSchedule, *Response, error) {
+	return s.scheduleRequest("GET", pid, schedule, "", nil, options)
+}
+
+// CreatePipelineScheduleOptions represents the available
+// CreatePipelineSchedule() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#create-a-new-pipeline-schedule
+type CreatePipelineScheduleOptions struct {
+	Description  string `url:"description" json:"description"`
+	Ref          string `url:"ref" json:"ref"`
+	Cron         string `url:"cron" json:"cron"`
+	CronTimezone string `url:"cron_timezone,omitempty" json:"cron_timezone,omitempty"`
+	Active       *bool  `url:"active,omitempty" json:"active,omitempty"`
+}
+
+// CreatePipelineSchedule creates a new pipeline schedule, owned by the
+// current user. The cron expression is evaluated in CronTimezone, UTC by
+// default.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#create-a-new-pipeline-schedule
+func (s *PipelineSchedulesService) CreatePipelineSchedule(
+	pid ProjectRef,
+	opt *CreatePipelineScheduleOptions,
+	options ...OptionFunc) (*PipelineSchedule, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipeline_schedules", project)
+
+	req, err := s.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ps := new(PipelineSchedule)
+	resp, err := s.client.Do(req, ps)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ps, resp, err
+}
+
+// EditPipelineScheduleOptions represents the available
+// EditPipelineSchedule() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#edit-a-pipeline-schedule
+type EditPipelineScheduleOptions struct {
+	Description  string `url:"description,omitempty" json:"description,omitempty"`
+	Ref          string `url:"ref,omitempty" json:"ref,omitempty"`
+	Cron         string `url:"cron,omitempty" json:"cron,omitempty"`
+	CronTimezone string `url:"cron_timezone,omitempty" json:"cron_timezone,omitempty"`
+	Active       *bool  `url:"active,omitempty" json:"active,omitempty"`
+}
+
+// EditPipelineSchedule updates a pipeline schedule. Use Active to pause or
+// resume it.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#edit-a-pipeline-schedule
+func (s *PipelineSchedulesService) EditPipelineSchedule(
+	pid ProjectRef,
+	schedule int,
+	opt *EditPipelineScheduleOptions,
+	options ...OptionFunc) (*PipelineSchedule, *Response, error) {
+	return s.scheduleRequest("PUT", pid, schedule, "", opt, options)
+}
+
+// TakePipelineScheduleOwnership makes the current user the owner of a
+// pipeline schedule, so its pipelines run with their permissions.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#take-ownership-of-a-pipeline-schedule
+func (s *PipelineSchedulesService) TakePipelineScheduleOwnership(
+	pid ProjectRef,
+	schedule int,
+	options ...OptionFunc) (*PipelineSchedule, *Response, error) {
+	return s.scheduleRequest("POST", pid, schedule, "/take_ownership", nil, options)
+}
+
+// scheduleRequest sends a request to the given path of a pipeline schedule,
+// and returns the resulting schedule.
+func (s *PipelineSchedulesService) scheduleRequest(
+	method string,
+	pid ProjectRef,
+	schedule int,
+	path string,
+	opt interface{},
+	options []OptionFunc) (*PipelineSchedule, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipeline_schedules/%d%s", project, schedule, path)
+
+	req, err := s.client.NewRequest(method, u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ps := new(PipelineSchedule)
+	resp, err := s.client.Do(req, ps)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ps, resp, err
+}
+
+// DeletePipelineSchedule deletes a pipeline schedule.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#delete-a-pipeline-schedule
+func (s *PipelineSchedulesService) DeletePipelineSchedule(
+	pid ProjectRef,
+	schedule int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipeline_schedules/%d", project, schedule)
+
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
+
+// RunPipelineSchedule runs a pipeline schedule now, without changing its
+// next run. The pipeline is created asynchronously.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#run-a-scheduled-pipeline-immediately
+func (s *PipelineSchedulesService) RunPipelineSchedule(
+	pid ProjectRef,
+	schedule int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipeline_schedules/%d/play", project, schedule)
+
+	req, err := s.client.NewRequest("POST", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
+
+// PipelineScheduleVariableOptions represents the available
+// CreatePipelineScheduleVariable() and EditPipelineScheduleVariable()
+// options. The type is "env_var" (the default) or "file".
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#pipeline-schedule-variables
+type PipelineScheduleVariableOptions struct {
+	Key          string `url:"key,omitempty" json:"key,omitempty"`
+	Value        string `url:"value" json:"value"`
+	VariableType string `url:"variable_type,omitempty" json:"variable_type,omitempty"`
+}
+
+// CreatePipelineScheduleVariable adds a variable to a pipeline schedule.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#create-a-new-pipeline-schedule-variable
+func (s *PipelineSchedulesService) CreatePipelineScheduleVariable(
+	pid ProjectRef,
+	schedule int,
+	opt *PipelineScheduleVariableOptions,
+	options ...OptionFunc) (*PipelineScheduleVariable, *Response, error) {
+	return s.variableRequest("POST", pid, schedule, "", opt, options)
+}
+
+// EditPipelineScheduleVariable updates the variable of a pipeline schedule
+// with the given key.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#edit-a-pipeline-schedule-variable
+func (s *PipelineSchedulesService) EditPipelineScheduleVariable(
+	pid ProjectRef,
+	schedule int,
+	key string,
+	opt *PipelineScheduleVariableOptions,
+	options ...OptionFunc) (*PipelineScheduleVariable, *Response, error) {
+	return s.variableRequest("PUT", pid, schedule, key, opt, options)
+}
+
+// DeletePipelineScheduleVariable deletes the variable of a pipeline schedule
+// with the given key, and returns it.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_schedules.html#delete-a-pipeline-schedule-variable
+func (s *PipelineSchedulesService) DeletePipelineScheduleVariable(
+	pid ProjectRef,
+	schedule int,
+	key string,
+	options ...OptionFunc) (*PipelineScheduleVariable, *Response, error) {
+	return s.variableRequest("DELETE", pid, schedule, key, nil, options)
+}
+
+// variableRequest sends a request to the variables of a pipeline schedule,
+// or to the variable with the given key, and returns the resulting variable.
+func (s *PipelineSchedulesService) variableRequest(
+	method string,
+	pid ProjectRef,
+	schedule int,
+	key string,
+	opt interface{},
+	options []OptionFunc) (*PipelineScheduleVariable, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipeline_schedules/%d/variables", project, schedule)
+	if key != "" {
+		u += "/" + url.PathEscape(key)
+	}
+
+	req, err := s.client.NewRequest(method, u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(PipelineScheduleVariable)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
@@ -0,0 +1,156 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListPipelineSchedules(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipeline_schedules", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"scope": "active"})
+		fmt.Fprint(w, `[{"id":13,"description":"nightly","ref":"master","cron":"0 1 * * *","active":true,"next_run_at":"2017-05-19T13:41:00.000Z"}]`)
+	})
+
+	opt := &ListPipelineSchedulesOptions{Scope: "active"}
+	schedules, _, err := client.PipelineSchedules.ListPipelineSchedules(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("PipelineSchedules.ListPipelineSchedules returned error: %v", err)
+	}
+
+	if len(schedules) != 1 || schedules[0].Cron != "0 1 * * *" || schedules[0].NextRunAt == nil {
+		t.Errorf("PipelineSchedules.ListPipelineSchedules returned %+v", schedules)
+	}
+}
+
+func TestCreatePipelineSchedule(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipeline_schedules", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"description":"nightly","ref":"master","cron":"0 1 * * *","cron_timezone":"UTC","active":false}`)
+		fmt.Fprint(w, `{"id":13,"description":"nightly","active":false}`)
+	})
+
+	opt := &CreatePipelineScheduleOptions{
+		Description:  "nightly",
+		Ref:          "master",
+		Cron:         "0 1 * * *",
+		CronTimezone: "UTC",
+		Active:       Bool(false),
+	}
+	schedule, _, err := client.PipelineSchedules.CreatePipelineSchedule(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("PipelineSchedules.CreatePipelineSchedule returned error: %v", err)
+	}
+
+	if schedule.ID != 13 || schedule.Active {
+		t.Errorf("PipelineSchedules.CreatePipelineSchedule returned %+v", schedule)
+	}
+}
+
+func TestEditPipelineSchedule(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipeline_schedules/13", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testBody(t, r, `{"cron":"0 2 * * *"}`)
+		fmt.Fprint(w, `{"id":13,"cron":"0 2 * * *"}`)
+	})
+
+	opt := &EditPipelineScheduleOptions{Cron: "0 2 * * *"}
+	schedule, _, err := client.PipelineSchedules.EditPipelineSchedule(ProjectID(1), 13, opt)
+	if err != nil {
+		t.Fatalf("PipelineSchedules.EditPipelineSchedule returned error: %v", err)
+	}
+
+	if schedule.Cron != "0 2 * * *" {
+		t.Errorf("PipelineSchedules.EditPipelineSchedule returned %+v", schedule)
+	}
+}
+
+func TestTakePipelineScheduleOwnershipAndRun(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipeline_schedules/13/take_ownership", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"id":13,"owner":{"username":"jdoe"}}`)
+	})
+	mux.HandleFunc("/projects/1/pipeline_schedules/13/play", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"message":"201 Created"}`)
+	})
+
+	schedule, _, err := client.PipelineSchedules.TakePipelineScheduleOwnership(ProjectID(1), 13)
+	if err != nil {
+		t.Fatalf("PipelineSchedules.TakePipelineScheduleOwnership returned error: %v", err)
+	}
+	if schedule.Owner == nil || schedule.Owner.Username != "jdoe" {
+		t.Errorf("PipelineSchedules.TakePipelineScheduleOwnership returned %+v", schedule)
+	}
+
+	if _, err := client.PipelineSchedules.RunPipelineSchedule(ProjectID(1), 13); err != nil {
+		t.Errorf("PipelineSchedules.RunPipelineSchedule returned error: %v", err)
+	}
+}
+
+func TestDeletePipelineSchedule(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipeline_schedules/13", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.PipelineSchedules.DeletePipelineSchedule(ProjectID(1), 13); err != nil {
+		t.Errorf("PipelineSchedules.DeletePipelineSchedule returned error: %v", err)
+	}
+}
+
+func TestPipelineScheduleVariables(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipeline_schedules/13/variables", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"key":"DEPLOY","value":"staging"}`)
+		fmt.Fprint(w, `{"key":"DEPLOY","value":"staging","variable_type":"env_var"}`)
+	})
+	mux.HandleFunc("/projects/1/pipeline_schedules/13/variables/DEPLOY", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			testBody(t, r, `{"value":"production"}`)
+			fmt.Fprint(w, `{"key":"DEPLOY","value":"production"}`)
+		case "DELETE":
+			fmt.Fprint(w, `{"key":"DEPLOY","value":"production"}`)
+		default:
+			t.Errorf("Request method: %s, want PUT or DELETE", r.Method)
+		}
+	})
+
+	v, _, err := client.PipelineSchedules.CreatePipelineScheduleVariable(ProjectID(1), 13,
+		&PipelineScheduleVariableOptions{Key: "DEPLOY", Value: "staging"})
+	if err != nil || v.VariableType != "env_var" {
+		t.Errorf("PipelineSchedules.CreatePipelineScheduleVariable returned %+v, %v", v, err)
+	}
+
+	v, _, err = client.PipelineSchedules.EditPipelineScheduleVariable(ProjectID(1), 13, "DEPLOY",
+		&PipelineScheduleVariableOptions{Value: "production"})
+	if err != nil || v.Value != "production" {
+		t.Errorf("PipelineSchedules.EditPipelineScheduleVariable returned %+v, %v", v, err)
+	}
+
+	v, _, err = client.PipelineSchedules.DeletePipelineScheduleVariable(ProjectID(1), 13, "DEPLOY")
+	if err != nil || v.Key != "DEPLOY" {
+		t.Errorf("PipelineSchedules.DeletePipelineScheduleVariable returned %+v, %v", v, err)
+	}
+}
@@ -0,0 +1,224 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"fmt"
+)
+
+// PipelineTriggersService handles communication with the pipeline trigger
+// related methods of the GitLab API. Triggers hold the tokens used to start
+// pipelines from outside GitLab.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/pipeline_triggers.html
+type PipelineTriggersService struct {
+	client *Client
+}
+
+// PipelineTrigger represents a pipeline trigger of a project.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/pipeline_triggers.html
+type PipelineTrigger struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	Token       string `json:"token"`
+	Owner       *User  `json:"owner"`
+	CreatedAt   *Time  `json:"created_at"`
+	UpdatedAt   *Time  `json:"updated_at"`
+	DeletedAt   *Time  `json:"deleted_at"`
+	LastUsed    *Time  `json:"last_used"`
+}
+
+func (t PipelineTrigger) String() string {
+	return Stringify(t)
+}
+
+// ListPipelineTriggersOptions represents the available ListPipelineTriggers()
+// options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_triggers.html#list-project-triggers
+type ListPipelineTriggersOptions struct {
+	ListOptions
+}
+
+// ListPipelineTriggers gets a list of the pipeline triggers of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_triggers.html#list-project-triggers
+func (s *PipelineTriggersService) ListPipelineTriggers(
+	pid ProjectRef,
+	opt *ListPipelineTriggersOptions,
+	options ...OptionFunc) ([]*PipelineTrigger, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/triggers", project)
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var t []*PipelineTrigger
+	resp, err := s.client.Do(req, &t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return t, resp, err
+}
+
+// GetPipelineTrigger gets a single pipeline trigger of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_triggers.html#get-trigger-details
+func (s *PipelineTriggersService) GetPipelineTrigger(
+	pid ProjectRef,
+	trigger int,
+	options ...OptionFunc) (*PipelineTrigger, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/triggers/%d", project, trigger)
+
+	req, err := s.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := new(PipelineTrigger)
+	resp, err := s.client.Do(req, t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return t, resp, err
+}
+
+// CreatePipelineTriggerOptions represents the available
+// CreatePipelineTrigger() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_triggers.html#create-a-project-trigger
+type CreatePipelineTriggerOptions struct {
+	Description string `url:"description,omitempty" json:"description,omitempty"`
+}
+
+// CreatePipelineTrigger creates a new pipeline trigger, owned by the current
+// user. Its Token is what TriggerPipeline is called with.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_triggers.html#create-a-project-trigger
+func (s *PipelineTriggersService) CreatePipelineTrigger(
+	pid ProjectRef,
+	opt *CreatePipelineTriggerOptions,
+	options ...OptionFunc) (*PipelineTrigger, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/triggers", project)
+
+	req, err := s.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := new(PipelineTrigger)
+	resp, err := s.client.Do(req, t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return t, resp, err
+}
+
+// DeletePipelineTrigger deletes a pipeline trigger, revoking its token.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipeline_triggers.html#remove-a-project-trigger
+func (s *PipelineTriggersService) DeletePipelineTrigger(
+	pid ProjectRef,
+	trigger int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("projects/%s/triggers/%d", project, trigger)
+
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
+
+// TriggerPipelineOptions represents the available TriggerPipeline() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/ci/triggers/README.html
+type TriggerPipelineOptions struct {
+	Token     string            `url:"token" json:"token"`
+	Ref       string            `url:"ref" json:"ref"`
+	Variables map[string]string `url:"variables,omitempty" json:"variables,omitempty"`
+}
+
+// TriggerPipeline starts a pipeline for a branch or tag of a project, using
+// the token of a trigger. The variables are passed to the jobs of the
+// pipeline.
+//
+// The token is enough to authenticate the call. API v3 triggers builds
+// instead, and only returns the ID of the trigger request.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/ci/triggers/README.html
+func (s *PipelineTriggersService) TriggerPipeline(
+	pid ProjectRef,
+	opt *TriggerPipelineOptions,
+	options ...OptionFunc) (*Pipeline, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/trigger/pipeline", project)
+	if s.client.apiVersion == APIVersion3 {
+		u = fmt.Sprintf("projects/%s/trigger/builds", project)
+	}
+
+	req, err := s.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := new(Pipeline)
+	resp, err := s.client.Do(req, p)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return p, resp, err
+}
@@ -0,0 +1,101 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListPipelineTriggers(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/triggers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":10,"description":"nightly","token":"6d056f63e50fe6f8c5f8f4aa10edb7"}]`)
+	})
+
+	triggers, _, err := client.PipelineTriggers.ListPipelineTriggers(ProjectID(1), nil)
+	if err != nil {
+		t.Fatalf("PipelineTriggers.ListPipelineTriggers returned error: %v", err)
+	}
+
+	if len(triggers) != 1 || triggers[0].ID != 10 || triggers[0].Description != "nightly" {
+		t.Errorf("PipelineTriggers.ListPipelineTriggers returned %+v", triggers)
+	}
+}
+
+func TestCreatePipelineTrigger(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/triggers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testJsonBody(t, r, values{"description": "nightly"})
+		fmt.Fprint(w, `{"id":10,"description":"nightly","token":"6d056f63e50fe6f8c5f8f4aa10edb7"}`)
+	})
+
+	opt := &CreatePipelineTriggerOptions{Description: "nightly"}
+	trigger, _, err := client.PipelineTriggers.CreatePipelineTrigger(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("PipelineTriggers.CreatePipelineTrigger returned error: %v", err)
+	}
+
+	if trigger.Token != "6d056f63e50fe6f8c5f8f4aa10edb7" {
+		t.Errorf("PipelineTriggers.CreatePipelineTrigger returned %+v", trigger)
+	}
+}
+
+func TestDeletePipelineTrigger(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/triggers/10", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.PipelineTriggers.DeletePipelineTrigger(ProjectID(1), 10); err != nil {
+		t.Errorf("PipelineTriggers.DeletePipelineTrigger returned error: %v", err)
+	}
+}
+
+func TestTriggerPipeline(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/trigger/pipeline", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"token":"secret","ref":"master","variables":{"NIGHTLY":"true"}}`)
+		fmt.Fprint(w, `{"id":48,"status":"pending","ref":"master"}`)
+	})
+
+	opt := &TriggerPipelineOptions{
+		Token:     "secret",
+		Ref:       "master",
+		Variables: map[string]string{"NIGHTLY": "true"},
+	}
+	p, _, err := client.PipelineTriggers.TriggerPipeline(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("PipelineTriggers.TriggerPipeline returned error: %v", err)
+	}
+
+	if p.ID != 48 || p.Status != Pending {
+		t.Errorf("PipelineTriggers.TriggerPipeline returned %+v", p)
+	}
+}
+
+func TestTriggerPipeline_v3(t *testing.T) {
+	mux, server, client := setupV3()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/trigger/builds", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"id":12}`)
+	})
+
+	opt := &TriggerPipelineOptions{Token: "secret", Ref: "master"}
+	if _, _, err := client.PipelineTriggers.TriggerPipeline(ProjectID(1), opt); err != nil {
+		t.Fatalf("PipelineTriggers.TriggerPipeline returned error: %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"fmt"
+)
+
+// PipelinesService handles communication with the pipeline related methods
+// of the GitLab API.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/pipelines.html
+type PipelinesService struct {
+	client *Client
+}
+
+// Pipeline represents a GitLab pipeline.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/pipelines.html
+type Pipeline struct {
+	ID         int        `json:"id"`
+	Status     BuildState `json:"status"`
+	Ref        string     `json:"ref"`
+	SHA        string     `json:"sha"`
+	BeforeSHA  string     `json:"before_sha"`
+	Tag        bool       `json:"tag"`
+	YamlErrors string     `json:"yaml_errors"`
+	User       struct {
+		Name      string `json:"name"`
+		Username  string `json:"username"`
+		ID        int    `json:"id"`
+		State     string `json:"state"`
+		AvatarURL string `json:"avatar_url"`
+		WebURL    string `json:"web_url"`
+	} `json:"user"`
+	CreatedAt   *Time  `json:"created_at"`
+	UpdatedAt   *Time  `json:"updated_at"`
+	StartedAt   *Time  `json:"started_at"`
+	FinishedAt  *Time  `json:"finished_at"`
+	CommittedAt *Time  `json:"committed_at"`
+	Duration    int    `json:"duration"`
+	Coverage    string `json:"coverage"`
+	WebURL      string `json:"web_url"`
+}
+
+func (p Pipeline) String() string {
+	return Stringify(p)
+}
+
+// Finished reports whether the pipeline is done running, whatever the
+// outcome.
+func (p *Pipeline) Finished() bool {
+	switch p.Status {
+	case Success, Failed, Canceled, Skipped:
+		return true
+	}
+	return false
+}
+
+// ListProjectPipelinesOptions represents the available ListProjectPipelines()
+// options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipelines.html#list-project-pipelines
+type ListProjectPipelinesOptions struct {
+	ListOptions
+	Scope      string     `url:"scope,omitempty" json:"scope,omitempty"`
+	Status     BuildState `url:"status,omitempty" json:"status,omitempty"`
+	Ref        string     `url:"ref,omitempty" json:"ref,omitempty"`
+	SHA        string     `url:"sha,omitempty" json:"sha,omitempty"`
+	YamlErrors bool       `url:"yaml_errors,omitempty" json:"yaml_errors,omitempty"`
+	Username   string     `url:"username,omitempty" json:"username,omitempty"`
+	OrderBy    string     `url:"order_by,omitempty" json:"order_by,omitempty"`
+	Sort       string     `url:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// ListProjectPipelines gets a list of the pipelines of a project, newest
+// first.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipelines.html#list-project-pipelines
+func (s *PipelinesService) ListProjectPipelines(
+	pid ProjectRef,
+	opt *ListProjectPipelinesOptions,
+	options ...OptionFunc) ([]*Pipeline, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipelines", project)
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var p []*Pipeline
+	resp, err := s.client.Do(req, &p)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return p, resp, err
+}
+
+// GetPipeline gets a single pipeline of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipelines.html#get-a-single-pipeline
+func (s *PipelinesService) GetPipeline(
+	pid ProjectRef,
+	pipeline int,
+	options ...OptionFunc) (*Pipeline, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipelines/%d", project, pipeline)
+
+	req, err := s.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := new(Pipeline)
+	resp, err := s.client.Do(req, p)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return p, resp, err
+}
+
+// PipelineVariable represents a variable passed to a new pipeline.
+type PipelineVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// CreatePipelineOptions represents the available CreatePipeline() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipelines.html#create-a-new-pipeline
+type CreatePipelineOptions struct {
+	Ref       string              `url:"ref" json:"ref"`
+	Variables []*PipelineVariable `url:"variables,omitempty" json:"variables,omitempty"`
+}
+
+// CreatePipeline creates a new pipeline for a branch or tag of a project.
+// Variables are only supported by API v4.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipelines.html#create-a-new-pipeline
+func (s *PipelinesService) CreatePipeline(
+	pid ProjectRef,
+	opt *CreatePipelineOptions,
+	options ...OptionFunc) (*Pipeline, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipeline", project)
+
+	req, err := s.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := new(Pipeline)
+	resp, err := s.client.Do(req, p)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return p, resp, err
+}
+
+// RetryPipeline retries the failed builds of a pipeline.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipelines.html#retry-failed-builds-in-a-pipeline
+func (s *PipelinesService) RetryPipeline(
+	pid ProjectRef,
+	pipeline int,
+	options ...OptionFunc) (*Pipeline, *Response, error) {
+	return s.pipelineAction(pid, pipeline, "retry", options)
+}
+
+// CancelPipeline cancels the running builds of a pipeline.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipelines.html#cancel-a-pipelines-builds
+func (s *PipelinesService) CancelPipeline(
+	pid ProjectRef,
+	pipeline int,
+	options ...OptionFunc) (*Pipeline, *Response, error) {
+	return s.pipelineAction(pid, pipeline, "cancel", options)
+}
+
+// pipelineAction posts the given action on a pipeline, and returns the
+// updated pipeline.
+func (s *PipelinesService) pipelineAction(
+	pid ProjectRef,
+	pipeline int,
+	action string,
+	options []OptionFunc) (*Pipeline, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipelines/%d/%s", project, pipeline, action)
+
+	req, err := s.client.NewRequest("POST", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := new(Pipeline)
+	resp, err := s.client.Do(req, p)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return p, resp, err
+}
+
+// DeletePipeline deletes a pipeline, along with its builds. Only supported
+// by API v4.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/pipelines.html#delete-a-pipeline
+func (s *PipelinesService) DeletePipeline(
+	pid ProjectRef,
+	pipeline int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("projects/%s/pipelines/%d", project, pipeline)
+
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
@@ -0,0 +1,115 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListProjectPipelines(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"status": "failed",
+			"ref":    "master",
+		})
+		fmt.Fprint(w, `[{"id":47,"status":"failed","ref":"master"},{"id":46,"status":"failed","ref":"master"}]`)
+	})
+
+	opt := &ListProjectPipelinesOptions{Status: Failed, Ref: "master"}
+	pipelines, _, err := client.Pipelines.ListProjectPipelines(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("Pipelines.ListProjectPipelines returned error: %v", err)
+	}
+
+	if len(pipelines) != 2 || pipelines[0].ID != 47 || pipelines[0].Status != Failed {
+		t.Errorf("Pipelines.ListProjectPipelines returned %+v", pipelines)
+	}
+}
+
+func TestGetPipeline(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipelines/47", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":47,"status":"running","user":{"username":"root"},"started_at":"2016-08-11T11:28:34.085Z","finished_at":null}`)
+	})
+
+	p, _, err := client.Pipelines.GetPipeline(ProjectID(1), 47)
+	if err != nil {
+		t.Fatalf("Pipelines.GetPipeline returned error: %v", err)
+	}
+
+	if p.ID != 47 || p.User.Username != "root" || p.StartedAt == nil || p.FinishedAt != nil {
+		t.Errorf("Pipelines.GetPipeline returned %+v", p)
+	}
+	if p.Finished() {
+		t.Errorf("Pipeline.Finished returned true for a %s pipeline", p.Status)
+	}
+}
+
+func TestCreatePipeline(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipeline", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"ref":"master","variables":[{"key":"DEPLOY","value":"staging"}]}`)
+		fmt.Fprint(w, `{"id":48,"status":"pending","ref":"master"}`)
+	})
+
+	opt := &CreatePipelineOptions{
+		Ref:       "master",
+		Variables: []*PipelineVariable{{Key: "DEPLOY", Value: "staging"}},
+	}
+	p, _, err := client.Pipelines.CreatePipeline(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("Pipelines.CreatePipeline returned error: %v", err)
+	}
+
+	if p.ID != 48 || p.Status != Pending {
+		t.Errorf("Pipelines.CreatePipeline returned %+v", p)
+	}
+}
+
+func TestRetryAndCancelPipeline(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipelines/46/retry", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"id":46,"status":"pending"}`)
+	})
+	mux.HandleFunc("/projects/1/pipelines/46/cancel", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"id":46,"status":"canceled"}`)
+	})
+
+	p, _, err := client.Pipelines.RetryPipeline(ProjectID(1), 46)
+	if err != nil || p.Status != Pending {
+		t.Errorf("Pipelines.RetryPipeline returned %+v, %v", p, err)
+	}
+
+	p, _, err = client.Pipelines.CancelPipeline(ProjectID(1), 46)
+	if err != nil || p.Status != Canceled || !p.Finished() {
+		t.Errorf("Pipelines.CancelPipeline returned %+v, %v", p, err)
+	}
+}
+
+func TestDeletePipeline(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/pipelines/46", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.Pipelines.DeletePipeline(ProjectID(1), 46); err != nil {
+		t.Errorf("Pipelines.DeletePipeline returned error: %v", err)
+	}
+}
@@ -19,8 +19,7 @@ package gitlab
 import (
 	"bytes"
 	"fmt"
-	"net/url"
-	"time"
+	"io"
 )
 
 // ProjectSnippetsService handles communication with the project snippets
@@ -39,16 +38,16 @@ type Snippet struct {
 	Title    string `json:"title"`
 	FileName string `json:"file_name"`
 	Author   struct {
-		ID        int       `json:"id"`
-		Username  string    `json:"username"`
-		Email     string    `json:"email"`
-		Name      string    `json:"name"`
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"created_at"`
+		ID        int    `json:"id"`
+		Username  string `json:"username"`
+		Email     string `json:"email"`
+		Name      string `json:"name"`
+		State     string `json:"state"`
+		CreatedAt Time   `json:"created_at"`
 	} `json:"author"`
-	ExpiresAt *time.Time `json:"expires_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *Time `json:"expires_at"`
+	UpdatedAt Time  `json:"updated_at"`
+	CreatedAt Time  `json:"created_at"`
 }
 
 func (s Snippet) String() string {
@@ -66,15 +65,16 @@ type ListSnippetsOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/project_snippets.html#list-snippets
 func (s *ProjectSnippetsService) ListSnippets(
-	pid interface{},
-	opt *ListSnippetsOptions) ([]*Snippet, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ListSnippetsOptions,
+	options ...OptionFunc) ([]*Snippet, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/snippets", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -93,15 +93,16 @@ func (s *ProjectSnippetsService) ListSnippets(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/project_snippets.html#single-snippet
 func (s *ProjectSnippetsService) GetSnippet(
-	pid interface{},
-	snippet int) (*Snippet, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	snippet int,
+	options ...OptionFunc) (*Snippet, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets/%d", url.QueryEscape(project), snippet)
+	u := fmt.Sprintf("projects/%s/snippets/%d", project, snippet)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -132,15 +133,16 @@ type CreateSnippetOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/project_snippets.html#create-new-snippet
 func (s *ProjectSnippetsService) CreateSnippet(
-	pid interface{},
-	opt *CreateSnippetOptions) (*Snippet, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CreateSnippetOptions,
+	options ...OptionFunc) (*Snippet, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/snippets", project)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -171,16 +173,17 @@ type UpdateSnippetOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/project_snippets.html#update-snippet
 func (s *ProjectSnippetsService) UpdateSnippet(
-	pid interface{},
+	pid ProjectRef,
 	snippet int,
-	opt *UpdateSnippetOptions) (*Snippet, *Response, error) {
-	project, err := parseID(pid)
+	opt *UpdateSnippetOptions,
+	options ...OptionFunc) (*Snippet, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets/%d", url.QueryEscape(project), snippet)
+	u := fmt.Sprintf("projects/%s/snippets/%d", project, snippet)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -200,14 +203,17 @@ func (s *ProjectSnippetsService) UpdateSnippet(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/project_snippets.html#delete-snippet
-func (s *ProjectSnippetsService) DeleteSnippet(pid interface{}, snippet int) (*Response, error) {
-	project, err := parseID(pid)
+func (s *ProjectSnippetsService) DeleteSnippet(
+	pid ProjectRef,
+	snippet int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets/%d", url.QueryEscape(project), snippet)
+	u := fmt.Sprintf("projects/%s/snippets/%d", project, snippet)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -225,24 +231,62 @@ func (s *ProjectSnippetsService) DeleteSnippet(pid interface{}, snippet int) (*R
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/project_snippets.html#snippet-content
 func (s *ProjectSnippetsService) SnippetContent(
-	pid interface{},
-	snippet int) ([]byte, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	snippet int,
+	options ...OptionFunc) ([]byte, *Response, error) {
+	var b bytes.Buffer
+	resp, err := s.StreamSnippetContent(pid, snippet, &b, options...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return b.Bytes(), resp, err
+}
+
+// StreamSnippetContent is like SnippetContent, but writes the content to w
+// as it is received.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/project_snippets.html#snippet-content
+func (s *ProjectSnippetsService) StreamSnippetContent(
+	pid ProjectRef,
+	snippet int,
+	w io.Writer,
+	options ...OptionFunc) (*Response, error) {
+	body, resp, err := s.SnippetContentReader(pid, snippet, options...)
+	if err != nil {
+		return resp, err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+	return resp, err
+}
+
+// SnippetContentReader is like SnippetContent, but returns the content as a
+// stream, which the caller must close.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/project_snippets.html#snippet-content
+func (s *ProjectSnippetsService) SnippetContentReader(
+	pid ProjectRef,
+	snippet int,
+	options ...OptionFunc) (io.ReadCloser, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/snippets/%d/raw", url.QueryEscape(project), snippet)
+	u := fmt.Sprintf("projects/%s/snippets/%d/raw", project, snippet)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var b bytes.Buffer
-	resp, err := s.client.Do(req, &b)
+	resp, err := s.client.doStream(req)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return b.Bytes(), resp, err
+	return resp.Body, resp, err
 }
@@ -0,0 +1,26 @@
+package gitlab
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestStreamSnippetContent(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/snippets/2/raw", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, "snippet")
+	})
+
+	var b bytes.Buffer
+	if _, err := client.ProjectSnippets.StreamSnippetContent(ProjectID(1), 2, &b); err != nil {
+		t.Fatalf("ProjectSnippets.StreamSnippetContent returned error: %v", err)
+	}
+	if b.String() != "snippet" {
+		t.Errorf("ProjectSnippets.StreamSnippetContent wrote %q, want %q", b.String(), "snippet")
+	}
+}
@@ -18,8 +18,7 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
-	"time"
+	"io"
 )
 
 // ProjectsService handles communication with the repositories related methods
@@ -39,6 +38,7 @@ type Project struct {
 	DefaultBranch        *string           `json:"default_branch"`
 	Public               *bool             `json:"public"`
 	VisibilityLevel      *VisibilityLevel  `json:"visibility_level"`
+	Visibility           *Visibility       `json:"visibility"`
 	SSHURLToRepo         *string           `json:"ssh_url_to_repo"`
 	HTTPURLToRepo        *string           `json:"http_url_to_repo"`
 	WebURL               *string           `json:"web_url"`
@@ -52,8 +52,8 @@ type Project struct {
 	MergeRequestsEnabled *bool             `json:"merge_requests_enabled"`
 	WikiEnabled          *bool             `json:"wiki_enabled"`
 	SnippetsEnabled      *bool             `json:"snippets_enabled"`
-	CreatedAt            *time.Time        `json:"created_at,omitempty"`
-	LastActivityAt       *time.Time        `json:"last_activity_at,omitempty"`
+	CreatedAt            *Time             `json:"created_at,omitempty"`
+	LastActivityAt       *Time             `json:"last_activity_at,omitempty"`
 	CreatorID            *int              `json:"creator_id"`
 	Namespace            *ProjectNamespace `json:"namespace"`
 	Archived             *bool             `json:"archived"`
@@ -62,13 +62,13 @@ type Project struct {
 }
 
 type ProjectNamespace struct {
-	CreatedAt   *time.Time `json:"created_at"`
-	Description *string    `json:"description"`
-	ID          *int       `json:"id"`
-	Name        *string    `json:"name"`
-	OwnerID     *int       `json:"owner_id"`
-	Path        *string    `json:"path"`
-	UpdatedAt   *time.Time `json:"updated_at"`
+	CreatedAt   *Time   `json:"created_at"`
+	Description *string `json:"description"`
+	ID          *int    `json:"id"`
+	Name        *string `json:"name"`
+	OwnerID     *int    `json:"owner_id"`
+	Path        *string `json:"path"`
+	UpdatedAt   *Time   `json:"updated_at"`
 }
 
 type Permissions struct {
@@ -105,8 +105,10 @@ type ListProjectsOptions struct {
 // ListProjects gets a list of projects accessible by the authenticated user.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/projects.html#list-projects
-func (s *ProjectsService) ListProjects(opt *ListProjectsOptions) ([]*Project, *Response, error) {
-	req, err := s.client.NewRequest("GET", "projects", opt)
+func (s *ProjectsService) ListProjects(
+	opt *ListProjectsOptions,
+	options ...OptionFunc) ([]*Project, *Response, error) {
+	req, err := s.client.NewRequest("GET", "projects", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -126,8 +128,22 @@ func (s *ProjectsService) ListProjects(opt *ListProjectsOptions) ([]*Project, *R
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#list-owned-projects
 func (s *ProjectsService) ListOwnedProjects(
-	opt *ListProjectsOptions) ([]*Project, *Response, error) {
-	req, err := s.client.NewRequest("GET", "projects/owned", opt)
+	opt *ListProjectsOptions,
+	options ...OptionFunc) ([]*Project, *Response, error) {
+	u, o := "projects/owned", interface{}(opt)
+	if s.client.apiVersion != APIVersion3 {
+		// API v4 dropped projects/owned in favour of the owned parameter.
+		v := struct {
+			ListProjectsOptions
+			Owned bool `url:"owned"`
+		}{Owned: true}
+		if opt != nil {
+			v.ListProjectsOptions = *opt
+		}
+		u, o = "projects", v
+	}
+
+	req, err := s.client.NewRequest("GET", u, o, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -145,8 +161,16 @@ func (s *ProjectsService) ListOwnedProjects(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#list-all-projects
-func (s *ProjectsService) ListAllProjects(opt *ListProjectsOptions) ([]*Project, *Response, error) {
-	req, err := s.client.NewRequest("GET", "projects/all", opt)
+func (s *ProjectsService) ListAllProjects(
+	opt *ListProjectsOptions,
+	options ...OptionFunc) ([]*Project, *Response, error) {
+	u := "projects/all"
+	if s.client.apiVersion != APIVersion3 {
+		// API v4 lists all projects to admins at projects.
+		u = "projects"
+	}
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -165,14 +189,16 @@ func (s *ProjectsService) ListAllProjects(opt *ListProjectsOptions) ([]*Project,
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#get-single-project
-func (s *ProjectsService) GetProject(pid interface{}) (*Project, *Response, error) {
-	project, err := parseID(pid)
+func (s *ProjectsService) GetProject(
+	pid ProjectRef,
+	options ...OptionFunc) (*Project, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s", project)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -203,10 +229,19 @@ type SearchProjectsOptions struct {
 // http://doc.gitlab.com/ce/api/projects.html#search-for-projects-by-name
 func (s *ProjectsService) SearchProjects(
 	query string,
-	opt *SearchProjectsOptions) ([]*Project, *Response, error) {
-	u := fmt.Sprintf("projects/search/%s", query)
+	opt *SearchProjectsOptions,
+	options ...OptionFunc) ([]*Project, *Response, error) {
+	u, o := fmt.Sprintf("projects/search/%s", query), interface{}(opt)
+	if s.client.apiVersion != APIVersion3 {
+		// API v4 dropped projects/search in favour of the search parameter.
+		v := &ListProjectsOptions{Search: query}
+		if opt != nil {
+			v.ListOptions, v.OrderBy, v.Sort = opt.ListOptions, opt.OrderBy, opt.Sort
+		}
+		u, o = "projects", v
+	}
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, o, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -232,6 +267,7 @@ type ProjectEvent struct {
 	TargetType     interface{} `json:"target_type"`
 	AuthorID       int         `json:"author_id"`
 	AuthorUsername string      `json:"author_username"`
+	Author         *Author     `json:"author"`
 	Data           struct {
 		Before     string `json:"before"`
 		After      string `json:"after"`
@@ -245,10 +281,10 @@ type ProjectEvent struct {
 			Homepage    string `json:"homepage"`
 		} `json:"repository"`
 		Commits []struct {
-			ID        string    `json:"id"`
-			Message   string    `json:"message"`
-			Timestamp time.Time `json:"timestamp"`
-			URL       string    `json:"url"`
+			ID        string `json:"id"`
+			Message   string `json:"message"`
+			Timestamp Time   `json:"timestamp"`
+			URL       string `json:"url"`
 			Author    struct {
 				Name  string `json:"name"`
 				Email string `json:"email"`
@@ -277,15 +313,16 @@ type GetProjectEventsOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#get-project-events
 func (s *ProjectsService) GetProjectEvents(
-	pid interface{},
-	opt *GetProjectEventsOptions) ([]*ProjectEvent, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *GetProjectEventsOptions,
+	options ...OptionFunc) ([]*ProjectEvent, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/events", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/events", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -313,6 +350,7 @@ type CreateProjectOptions struct {
 	SnippetsEnabled      bool            `url:"snippets_enabled,omitempty" json:"snippets_enabled,omitempty"`
 	Public               bool            `url:"public,omitempty" json:"public,omitempty"`
 	VisibilityLevel      VisibilityLevel `url:"visibility_level,omitempty" json:"visibility_level,omitempty"`
+	Visibility           Visibility      `url:"visibility,omitempty" json:"visibility,omitempty"`
 	ImportURL            string          `url:"import_url,omitempty" json:"import_url,omitempty"`
 }
 
@@ -320,8 +358,9 @@ type CreateProjectOptions struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/projects.html#create-project
 func (s *ProjectsService) CreateProject(
-	opt *CreateProjectOptions) (*Project, *Response, error) {
-	req, err := s.client.NewRequest("POST", "projects", opt)
+	opt *CreateProjectOptions,
+	options ...OptionFunc) (*Project, *Response, error) {
+	req, err := s.client.NewRequest("POST", "projects", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -350,6 +389,7 @@ type CreateProjectForUserOptions struct {
 	SnippetsEnabled      bool            `url:"snippets_enabled,omitempty" json:"snippets_enabled,omitempty"`
 	Public               bool            `url:"public,omitempty" json:"public,omitempty"`
 	VisibilityLevel      VisibilityLevel `url:"visibility_level,omitempty" json:"visibility_level,omitempty"`
+	Visibility           Visibility      `url:"visibility,omitempty" json:"visibility,omitempty"`
 	ImportURL            string          `url:"import_url,omitempty" json:"import_url,omitempty"`
 }
 
@@ -360,10 +400,11 @@ type CreateProjectForUserOptions struct {
 // http://doc.gitlab.com/ce/api/projects.html#create-project-for-user
 func (s *ProjectsService) CreateProjectForUser(
 	user int,
-	opt *CreateProjectForUserOptions) (*Project, *Response, error) {
+	opt *CreateProjectForUserOptions,
+	options ...OptionFunc) (*Project, *Response, error) {
 	u := fmt.Sprintf("projects/user/%d", user)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -391,21 +432,23 @@ type EditProjectOptions struct {
 	SnippetsEnabled      bool            `url:"snippets_enabled,omitempty" json:"snippets_enabled,omitempty"`
 	Public               bool            `url:"public,omitempty" json:"public,omitempty"`
 	VisibilityLevel      VisibilityLevel `url:"visibility_level,omitempty" json:"visibility_level,omitempty"`
+	Visibility           Visibility      `url:"visibility,omitempty" json:"visibility,omitempty"`
 }
 
 // EditProject updates an existing project.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/projects.html#edit-project
 func (s *ProjectsService) EditProject(
-	pid interface{},
-	opt *EditProjectOptions) (*Project, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *EditProjectOptions,
+	options ...OptionFunc) (*Project, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s", project)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -423,14 +466,19 @@ func (s *ProjectsService) EditProject(
 // user.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/projects.html#fork-project
-func (s *ProjectsService) ForkProject(pid interface{}) (*Project, *Response, error) {
-	project, err := parseID(pid)
+func (s *ProjectsService) ForkProject(
+	pid ProjectRef,
+	options ...OptionFunc) (*Project, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/fork/%s", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/fork", project)
+	if s.client.apiVersion == APIVersion3 {
+		u = fmt.Sprintf("projects/fork/%s", project)
+	}
 
-	req, err := s.client.NewRequest("POST", u, nil)
+	req, err := s.client.NewRequest("POST", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -448,14 +496,14 @@ func (s *ProjectsService) ForkProject(pid interface{}) (*Project, *Response, err
 // (issues, merge requests etc.)
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/projects.html#remove-project
-func (s *ProjectsService) DeleteProject(pid interface{}) (*Response, error) {
-	project, err := parseID(pid)
+func (s *ProjectsService) DeleteProject(pid ProjectRef, options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s", project)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -473,13 +521,13 @@ func (s *ProjectsService) DeleteProject(pid interface{}) (*Response, error) {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#list-project-team-members
 type ProjectMember struct {
-	ID          int       `json:"id"`
-	Username    string    `json:"username"`
-	Email       string    `json:"email"`
-	Name        string    `json:"name"`
-	State       string    `json:"state"`
-	CreatedAt   time.Time `json:"created_at"`
-	AccessLevel int       `json:"access_level"`
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	CreatedAt   Time   `json:"created_at"`
+	AccessLevel int    `json:"access_level"`
 }
 
 // ListProjectMembersOptions represents the available ListProjectMembers()
@@ -497,15 +545,16 @@ type ListProjectMembersOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#list-project-team-members
 func (s *ProjectsService) ListProjectMembers(
-	pid interface{},
-	opt *ListProjectMembersOptions) ([]*ProjectMember, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ListProjectMembersOptions,
+	options ...OptionFunc) ([]*ProjectMember, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/members", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/members", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -524,15 +573,16 @@ func (s *ProjectsService) ListProjectMembers(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#get-project-team-member
 func (s *ProjectsService) GetProjectMember(
-	pid interface{},
-	user int) (*ProjectMember, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	user int,
+	options ...OptionFunc) (*ProjectMember, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/members/%d", url.QueryEscape(project), user)
+	u := fmt.Sprintf("projects/%s/members/%d", project, user)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -563,15 +613,16 @@ type AddProjectMemberOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#add-project-team-member
 func (s *ProjectsService) AddProjectMember(
-	pid interface{},
-	opt *AddProjectMemberOptions) (*ProjectMember, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *AddProjectMemberOptions,
+	options ...OptionFunc) (*ProjectMember, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/members", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/members", project)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -598,16 +649,17 @@ type EditProjectMemberOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#edit-project-team-member
 func (s *ProjectsService) EditProjectMember(
-	pid interface{},
+	pid ProjectRef,
 	user int,
-	opt *EditProjectMemberOptions) (*ProjectMember, *Response, error) {
-	project, err := parseID(pid)
+	opt *EditProjectMemberOptions,
+	options ...OptionFunc) (*ProjectMember, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/members/%d", url.QueryEscape(project), user)
+	u := fmt.Sprintf("projects/%s/members/%d", project, user)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -625,14 +677,17 @@ func (s *ProjectsService) EditProjectMember(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#remove-project-team-member
-func (s *ProjectsService) DeleteProjectMember(pid interface{}, user int) (*Response, error) {
-	project, err := parseID(pid)
+func (s *ProjectsService) DeleteProjectMember(
+	pid ProjectRef,
+	user int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/members/%d", url.QueryEscape(project), user)
+	u := fmt.Sprintf("projects/%s/members/%d", project, user)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -650,13 +705,16 @@ func (s *ProjectsService) DeleteProjectMember(pid interface{}, user int) (*Respo
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#list-project-hooks
 type ProjectHook struct {
-	ID                  int       `json:"id"`
-	URL                 string    `json:"url"`
-	ProjectID           int       `json:"project_id"`
-	PushEvents          bool      `json:"push_events"`
-	IssuesEvents        bool      `json:"issues_events"`
-	MergeRequestsEvents bool      `json:"merge_requests_events"`
-	CreatedAt           time.Time `json:"created_at"`
+	ID                  int    `json:"id"`
+	URL                 string `json:"url"`
+	ProjectID           int    `json:"project_id"`
+	PushEvents          bool   `json:"push_events"`
+	IssuesEvents        bool   `json:"issues_events"`
+	MergeRequestsEvents bool   `json:"merge_requests_events"`
+	TagPushEvents       bool   `json:"tag_push_events"`
+	BuildEvents         bool   `json:"build_events"`
+	JobEvents           bool   `json:"job_events"`
+	CreatedAt           Time   `json:"created_at"`
 }
 
 // ListProjectHooksOptions represents the available ListProjectHooks() options.
@@ -671,15 +729,16 @@ type ListProjectHooksOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#list-project-hooks
 func (s *ProjectsService) ListProjectHooks(
-	pid interface{},
-	opt *ListProjectHooksOptions) ([]*ProjectHook, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ListProjectHooksOptions,
+	options ...OptionFunc) ([]*ProjectHook, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/hooks", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/hooks", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -698,15 +757,16 @@ func (s *ProjectsService) ListProjectHooks(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#get-project-hook
 func (s *ProjectsService) GetProjectHook(
-	pid interface{},
-	hook int) (*ProjectHook, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	hook int,
+	options ...OptionFunc) (*ProjectHook, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/hooks/%d", url.QueryEscape(project), hook)
+	u := fmt.Sprintf("projects/%s/hooks/%d", project, hook)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -730,6 +790,8 @@ type AddProjectHookOptions struct {
 	IssuesEvents        bool   `url:"issues_events,omitempty" json:"issues_events,omitempty"`
 	MergeRequestsEvents bool   `url:"merge_requests_events,omitempty" json:"merge_requests_events,omitempty"`
 	TagPushEvents       bool   `url:"tag_push_events,omitempty" json:"tag_push_events,omitempty"`
+	BuildEvents         bool   `url:"build_events,omitempty" json:"build_events,omitempty"`
+	JobEvents           bool   `url:"job_events,omitempty" json:"job_events,omitempty"`
 }
 
 // AddProjectHook adds a hook to a specified project.
@@ -737,15 +799,16 @@ type AddProjectHookOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#add-project-hook
 func (s *ProjectsService) AddProjectHook(
-	pid interface{},
-	opt *AddProjectHookOptions) (*ProjectHook, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *AddProjectHookOptions,
+	options ...OptionFunc) (*ProjectHook, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/hooks", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/hooks", project)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -769,6 +832,8 @@ type EditProjectHookOptions struct {
 	IssuesEvents        bool   `url:"issues_events,omitempty" json:"issues_events,omitempty"`
 	MergeRequestsEvents bool   `url:"merge_requests_events,omitempty" json:"merge_requests_events,omitempty"`
 	TagPushEvents       bool   `url:"tag_push_events,omitempty" json:"tag_push_events,omitempty"`
+	BuildEvents         bool   `url:"build_events,omitempty" json:"build_events,omitempty"`
+	JobEvents           bool   `url:"job_events,omitempty" json:"job_events,omitempty"`
 }
 
 // EditProjectHook edits a hook for a specified project.
@@ -776,16 +841,17 @@ type EditProjectHookOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#edit-project-hook
 func (s *ProjectsService) EditProjectHook(
-	pid interface{},
+	pid ProjectRef,
 	hook int,
-	opt *EditProjectHookOptions) (*ProjectHook, *Response, error) {
-	project, err := parseID(pid)
+	opt *EditProjectHookOptions,
+	options ...OptionFunc) (*ProjectHook, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/hooks/%d", url.QueryEscape(project), hook)
+	u := fmt.Sprintf("projects/%s/hooks/%d", project, hook)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -804,14 +870,17 @@ func (s *ProjectsService) EditProjectHook(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#delete-project-hook
-func (s *ProjectsService) DeleteProjectHook(pid interface{}, hook int) (*Response, error) {
-	project, err := parseID(pid)
+func (s *ProjectsService) DeleteProjectHook(
+	pid ProjectRef,
+	hook int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/hooks/%d", url.QueryEscape(project), hook)
+	u := fmt.Sprintf("projects/%s/hooks/%d", project, hook)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -829,11 +898,11 @@ func (s *ProjectsService) DeleteProjectHook(pid interface{}, hook int) (*Respons
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#admin-fork-relation
 type ProjectForkRelation struct {
-	ID                  int       `json:"id"`
-	ForkedToProjectID   int       `json:"forked_to_project_id"`
-	ForkedFromProjectID int       `json:"forked_from_project_id"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                  int  `json:"id"`
+	ForkedToProjectID   int  `json:"forked_to_project_id"`
+	ForkedFromProjectID int  `json:"forked_from_project_id"`
+	CreatedAt           Time `json:"created_at"`
+	UpdatedAt           Time `json:"updated_at"`
 }
 
 // CreateProjectForkRelation creates a forked from/to relation between
@@ -843,10 +912,11 @@ type ProjectForkRelation struct {
 // http://doc.gitlab.com/ce/api/projects.html#create-a-forked-fromto-relation-between-existing-projects.
 func (s *ProjectsService) CreateProjectForkRelation(
 	pid int,
-	fork int) (*ProjectForkRelation, *Response, error) {
+	fork int,
+	options ...OptionFunc) (*ProjectForkRelation, *Response, error) {
 	u := fmt.Sprintf("projects/%d/fork/%d", pid, fork)
 
-	req, err := s.client.NewRequest("POST", u, nil)
+	req, err := s.client.NewRequest("POST", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -864,10 +934,12 @@ func (s *ProjectsService) CreateProjectForkRelation(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/projects.html#delete-an-existing-forked-from-relationship
-func (s *ProjectsService) DeleteProjectForkRelation(pid int) (*Response, error) {
+func (s *ProjectsService) DeleteProjectForkRelation(
+	pid int,
+	options ...OptionFunc) (*Response, error) {
 	u := fmt.Sprintf("projects/%d/fork", pid)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -879,3 +951,158 @@ func (s *ProjectsService) DeleteProjectForkRelation(pid int) (*Response, error)
 
 	return resp, err
 }
+
+// ProjectFile represents a file uploaded to a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/projects.html#upload-a-file
+type ProjectFile struct {
+	Alt      string `json:"alt"`
+	URL      string `json:"url"`
+	Markdown string `json:"markdown"`
+}
+
+func (s ProjectFile) String() string {
+	return Stringify(s)
+}
+
+// UploadFile uploads a file to a project, so it can be referenced in issues,
+// merge requests and notes. The Markdown field of the returned ProjectFile
+// holds a ready to use link to the file.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/projects.html#upload-a-file
+func (s *ProjectsService) UploadFile(
+	pid ProjectRef,
+	content io.Reader,
+	filename string,
+	options ...OptionFunc) (*ProjectFile, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/uploads", project)
+
+	files := []FormFile{{Field: "file", Name: filename, Content: content}}
+	req, err := s.client.NewMultipartRequest("POST", u, nil, files, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f := new(ProjectFile)
+	resp, err := s.client.Do(req, f)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return f, resp, err
+}
+
+// UploadAvatar uploads an avatar for a project.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/projects.html#edit-project
+func (s *ProjectsService) UploadAvatar(
+	pid ProjectRef,
+	avatar io.Reader,
+	filename string,
+	options ...OptionFunc) (*Project, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s", project)
+
+	files := []FormFile{{Field: "avatar", Name: filename, Content: avatar}}
+	req, err := s.client.NewMultipartRequest("PUT", u, nil, files, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := new(Project)
+	resp, err := s.client.Do(req, p)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return p, resp, err
+}
+
+// ImportStatus represents the status of a project import.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/project_import_export.html#import-status
+type ImportStatus struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	ImportStatus      string `json:"import_status"`
+	ImportError       string `json:"import_error"`
+}
+
+func (s ImportStatus) String() string {
+	return Stringify(s)
+}
+
+// ImportProjectOptions represents the available ImportProject() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/project_import_export.html#import-a-file
+type ImportProjectOptions struct {
+	Path      string `url:"path,omitempty" json:"path,omitempty"`
+	Namespace string `url:"namespace,omitempty" json:"namespace,omitempty"`
+	Overwrite bool   `url:"overwrite,omitempty" json:"overwrite,omitempty"`
+}
+
+// ImportProject imports a project from a file created by a project export.
+// The import runs in the background, use GetImportStatus to follow it. The
+// archive is streamed; the request is only retried if it implements io.Seeker.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/project_import_export.html#import-a-file
+func (s *ProjectsService) ImportProject(
+	archive io.Reader,
+	filename string,
+	opt *ImportProjectOptions,
+	options ...OptionFunc) (*ImportStatus, *Response, error) {
+	files := []FormFile{{Field: "file", Name: filename, Content: archive}}
+	req, err := s.client.NewMultipartRequest("POST", "projects/import", opt, files, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	is := new(ImportStatus)
+	resp, err := s.client.Do(req, is)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return is, resp, err
+}
+
+// GetImportStatus gets the status of the import of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/project_import_export.html#import-status
+func (s *ProjectsService) GetImportStatus(
+	pid ProjectRef,
+	options ...OptionFunc) (*ImportStatus, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/import", project)
+
+	req, err := s.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	is := new(ImportStatus)
+	resp, err := s.client.Do(req, is)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return is, resp, err
+}
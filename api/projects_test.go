@@ -25,7 +25,7 @@ func TestListProjects(t *testing.T) {
 		fmt.Fprint(w, `[{"id":1},{"id":2}]`)
 	})
 
-	opt := &ListProjectsOptions{ListOptions{2, 3}, true, "name", "asc", "query", true}
+	opt := &ListProjectsOptions{ListOptions{Page: 2, PerPage: 3}, true, "name", "asc", "query", true}
 	projects, _, err := client.Projects.ListProjects(opt)
 
 	if err != nil {
@@ -39,7 +39,7 @@ func TestListProjects(t *testing.T) {
 }
 
 func TestListOwnedProjects(t *testing.T) {
-	mux, server, client := setup()
+	mux, server, client := setupV3()
 	defer teardown(server)
 
 	mux.HandleFunc("/projects/owned", func(w http.ResponseWriter, r *http.Request) {
@@ -56,7 +56,7 @@ func TestListOwnedProjects(t *testing.T) {
 		fmt.Fprint(w, `[{"id":1},{"id":2}]`)
 	})
 
-	opt := &ListProjectsOptions{ListOptions{2, 3}, true, "name", "asc", "query", true}
+	opt := &ListProjectsOptions{ListOptions{Page: 2, PerPage: 3}, true, "name", "asc", "query", true}
 	projects, _, err := client.Projects.ListOwnedProjects(opt)
 
 	if err != nil {
@@ -70,7 +70,7 @@ func TestListOwnedProjects(t *testing.T) {
 }
 
 func TestListAllProjects(t *testing.T) {
-	mux, server, client := setup()
+	mux, server, client := setupV3()
 	defer teardown(server)
 
 	mux.HandleFunc("/projects/all", func(w http.ResponseWriter, r *http.Request) {
@@ -87,7 +87,7 @@ func TestListAllProjects(t *testing.T) {
 		fmt.Fprint(w, `[{"id":1},{"id":2}]`)
 	})
 
-	opt := &ListProjectsOptions{ListOptions{2, 3}, true, "name", "asc", "query", true}
+	opt := &ListProjectsOptions{ListOptions{Page: 2, PerPage: 3}, true, "name", "asc", "query", true}
 	projects, _, err := client.Projects.ListAllProjects(opt)
 
 	if err != nil {
@@ -110,7 +110,7 @@ func TestGetProject_byID(t *testing.T) {
 	})
 	want := &Project{ID: Int(1)}
 
-	project, _, err := client.Projects.GetProject(1)
+	project, _, err := client.Projects.GetProject(ProjectID(1))
 
 	if err != nil {
 		t.Fatalf("Projects.GetProject returns an error: %v", err)
@@ -132,7 +132,7 @@ func TestGetProject_byName(t *testing.T) {
 	})
 	want := &Project{ID: Int(1)}
 
-	project, _, err := client.Projects.GetProject("namespace/name")
+	project, _, err := client.Projects.GetProject(ProjectPath("namespace/name"))
 
 	if err != nil {
 		t.Fatalf("Projects.GetProject returns an error: %v", err)
@@ -144,7 +144,7 @@ func TestGetProject_byName(t *testing.T) {
 }
 
 func TestSearchProjects(t *testing.T) {
-	mux, server, client := setup()
+	mux, server, client := setupV3()
 	defer teardown(server)
 
 	mux.HandleFunc("/projects/search/query", func(w http.ResponseWriter, r *http.Request) {
@@ -158,7 +158,89 @@ func TestSearchProjects(t *testing.T) {
 		fmt.Fprint(w, `[{"id":1},{"id":2}]`)
 	})
 
-	opt := &SearchProjectsOptions{ListOptions{2, 3}, "name", "asc"}
+	opt := &SearchProjectsOptions{ListOptions{Page: 2, PerPage: 3}, "name", "asc"}
+	projects, _, err := client.Projects.SearchProjects("query", opt)
+
+	if err != nil {
+		t.Errorf("Projects.SearchProjects returned error: %v", err)
+	}
+
+	want := []*Project{{ID: Int(1)}, {ID: Int(2)}}
+	if !reflect.DeepEqual(want, projects) {
+		t.Errorf("Projects.SearchProjects returned %+v, want %+v", projects, want)
+	}
+}
+
+func TestListOwnedProjects_v4(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"page":     "2",
+			"per_page": "3",
+			"owned":    "true",
+		})
+		fmt.Fprint(w, `[{"id":1},{"id":2}]`)
+	})
+
+	opt := &ListProjectsOptions{ListOptions: ListOptions{Page: 2, PerPage: 3}}
+	projects, _, err := client.Projects.ListOwnedProjects(opt)
+
+	if err != nil {
+		t.Errorf("Projects.ListOwnedProjects returned error: %v", err)
+	}
+
+	want := []*Project{{ID: Int(1)}, {ID: Int(2)}}
+	if !reflect.DeepEqual(want, projects) {
+		t.Errorf("Projects.ListOwnedProjects returned %+v, want %+v", projects, want)
+	}
+}
+
+func TestListAllProjects_v4(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"page":     "2",
+			"per_page": "3",
+		})
+		fmt.Fprint(w, `[{"id":1},{"id":2}]`)
+	})
+
+	opt := &ListProjectsOptions{ListOptions: ListOptions{Page: 2, PerPage: 3}}
+	projects, _, err := client.Projects.ListAllProjects(opt)
+
+	if err != nil {
+		t.Errorf("Projects.ListAllProjects returned error: %v", err)
+	}
+
+	want := []*Project{{ID: Int(1)}, {ID: Int(2)}}
+	if !reflect.DeepEqual(want, projects) {
+		t.Errorf("Projects.ListAllProjects returned %+v, want %+v", projects, want)
+	}
+}
+
+func TestSearchProjects_v4(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"page":     "2",
+			"per_page": "3",
+			"order_by": "name",
+			"sort":     "asc",
+			"search":   "query",
+		})
+		fmt.Fprint(w, `[{"id":1},{"id":2}]`)
+	})
+
+	opt := &SearchProjectsOptions{ListOptions{Page: 2, PerPage: 3}, "name", "asc"}
 	projects, _, err := client.Projects.SearchProjects("query", opt)
 
 	if err != nil {
@@ -171,6 +253,37 @@ func TestSearchProjects(t *testing.T) {
 	}
 }
 
+func TestForkProject(t *testing.T) {
+	for _, v := range []struct {
+		version APIVersion
+		path    string
+	}{
+		{APIVersion3, "/projects/fork/namespace%2Fname"},
+		{APIVersion4, "/projects/namespace%2Fname/fork"},
+	} {
+		mux, server, client := setup()
+		client.SetAPIVersion(v.version)
+
+		mux.HandleFunc("/projects/", func(w http.ResponseWriter, r *http.Request) {
+			testUrl(t, r, v.path)
+			testMethod(t, r, "POST")
+			fmt.Fprint(w, `{"id":2}`)
+		})
+
+		project, _, err := client.Projects.ForkProject(ProjectPath("namespace/name"))
+		if err != nil {
+			t.Errorf("Projects.ForkProject (%s) returned error: %v", v.version, err)
+		}
+
+		want := &Project{ID: Int(2)}
+		if !reflect.DeepEqual(want, project) {
+			t.Errorf("Projects.ForkProject (%s) returned %+v, want %+v", v.version, project, want)
+		}
+
+		teardown(server)
+	}
+}
+
 func TestCreateProject(t *testing.T) {
 	mux, server, client := setup()
 	defer teardown(server)
@@ -0,0 +1,181 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ProtectedTagsService handles communication with the protected tag related
+// methods of the GitLab API. Only supported by API v4.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/protected_tags.html
+type ProtectedTagsService struct {
+	client *Client
+}
+
+// ProtectedTag represents a protected tag, or a wildcard matching tags
+// (e.g. "v*").
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/protected_tags.html
+type ProtectedTag struct {
+	Name               string                  `json:"name"`
+	CreateAccessLevels []*TagAccessDescription `json:"create_access_levels"`
+}
+
+// TagAccessDescription represents an access level allowed to create the
+// protected tags.
+type TagAccessDescription struct {
+	AccessLevel            AccessLevel `json:"access_level"`
+	AccessLevelDescription string      `json:"access_level_description"`
+}
+
+func (t ProtectedTag) String() string {
+	return Stringify(t)
+}
+
+// ListProtectedTagsOptions represents the available ListProtectedTags()
+// options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/protected_tags.html#list-protected-tags
+type ListProtectedTagsOptions struct {
+	ListOptions
+}
+
+// ListProtectedTags gets a list of the protected tags of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/protected_tags.html#list-protected-tags
+func (s *ProtectedTagsService) ListProtectedTags(
+	pid ProjectRef,
+	opt *ListProtectedTagsOptions,
+	options ...OptionFunc) ([]*ProtectedTag, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/protected_tags", project)
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var t []*ProtectedTag
+	resp, err := s.client.Do(req, &t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return t, resp, err
+}
+
+// GetProtectedTag gets a single protected tag or wildcard of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/protected_tags.html#get-a-single-protected-tag-or-wildcard-protected-tag
+func (s *ProtectedTagsService) GetProtectedTag(
+	pid ProjectRef,
+	tag string,
+	options ...OptionFunc) (*ProtectedTag, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/protected_tags/%s", project, url.PathEscape(tag))
+
+	req, err := s.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := new(ProtectedTag)
+	resp, err := s.client.Do(req, t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return t, resp, err
+}
+
+// ProtectTagOptions represents the available ProtectTag() options. Without
+// CreateAccessLevel, only masters can create the tags; use NoPermissions to
+// forbid their creation.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/protected_tags.html#protect-repository-tags
+type ProtectTagOptions struct {
+	Name              string       `url:"name" json:"name"`
+	CreateAccessLevel *AccessLevel `url:"create_access_level,omitempty" json:"create_access_level,omitempty"`
+}
+
+// ProtectTag protects a single tag, or the tags matching a wildcard.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/protected_tags.html#protect-repository-tags
+func (s *ProtectedTagsService) ProtectTag(
+	pid ProjectRef,
+	opt *ProtectTagOptions,
+	options ...OptionFunc) (*ProtectedTag, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/protected_tags", project)
+
+	req, err := s.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := new(ProtectedTag)
+	resp, err := s.client.Do(req, t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return t, resp, err
+}
+
+// UnprotectTag unprotects a tag or wildcard.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/protected_tags.html#unprotect-repository-tags
+func (s *ProtectedTagsService) UnprotectTag(
+	pid ProjectRef,
+	tag string,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("projects/%s/protected_tags/%s", project, url.PathEscape(tag))
+
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
@@ -0,0 +1,76 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListProtectedTags(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/protected_tags", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"name":"release-*","create_access_levels":[{"access_level":40,"access_level_description":"Masters"}]}]`)
+	})
+
+	tags, _, err := client.ProtectedTags.ListProtectedTags(ProjectID(1), nil)
+	if err != nil {
+		t.Fatalf("ProtectedTags.ListProtectedTags returned error: %v", err)
+	}
+
+	if len(tags) != 1 || tags[0].CreateAccessLevels[0].AccessLevel != MasterPermissions {
+		t.Errorf("ProtectedTags.ListProtectedTags returned %+v", tags)
+	}
+}
+
+func TestGetProtectedTag(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/protected_tags/release-*", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"name":"release-*"}`)
+	})
+
+	tag, _, err := client.ProtectedTags.GetProtectedTag(ProjectID(1), "release-*")
+	if err != nil || tag.Name != "release-*" {
+		t.Errorf("ProtectedTags.GetProtectedTag returned %+v, %v", tag, err)
+	}
+}
+
+func TestProtectTag(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/protected_tags", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"name":"v*","create_access_level":0}`)
+		fmt.Fprint(w, `{"name":"v*","create_access_levels":[{"access_level":0,"access_level_description":"No one"}]}`)
+	})
+
+	opt := &ProtectTagOptions{Name: "v*", CreateAccessLevel: Access(NoPermissions)}
+	tag, _, err := client.ProtectedTags.ProtectTag(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("ProtectedTags.ProtectTag returned error: %v", err)
+	}
+
+	if tag.Name != "v*" || tag.CreateAccessLevels[0].AccessLevelDescription != "No one" {
+		t.Errorf("ProtectedTags.ProtectTag returned %+v", tag)
+	}
+}
+
+func TestUnprotectTag(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/protected_tags/v*", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.ProtectedTags.UnprotectTag(ProjectID(1), "v*"); err != nil {
+		t.Errorf("ProtectedTags.UnprotectTag returned error: %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate of the requests sent to a
+// GitLab instance. It adapts to the RateLimit-Remaining and RateLimit-Reset
+// headers returned by GitLab: the remaining requests are spread over the
+// rest of the rate limit window, so requests are delayed instead of being
+// throttled with 429 Too Many Requests.
+//
+// A RateLimiter is safe for concurrent use, and can be shared by several
+// clients.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+
+	// The rate at which GitLab allows requests until the reset of its rate
+	// limit window, as announced by the headers of the last response.
+	windowRate  float64
+	windowReset time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing rate requests per second on
+// average, with bursts of up to burst requests.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// SetLimit changes the rate and burst of l.
+func (l *RateLimiter) SetLimit(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(time.Now())
+	l.rate = rate
+	l.burst = burst
+	if l.tokens > float64(burst) {
+		l.tokens = float64(burst)
+	}
+}
+
+// Limit returns the rate and burst of l.
+func (l *RateLimiter) Limit() (float64, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate, l.burst
+}
+
+// Wait blocks until a request may be sent, or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	wait := l.reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		// The request will not be sent, give its token back.
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Update adapts l to the rate limit status reported by GitLab in the headers
+// of a response. Headers without rate limit status are ignored.
+func (l *RateLimiter) Update(h http.Header) {
+	l.update(h, time.Now())
+}
+
+func (l *RateLimiter) update(h http.Header, now time.Time) {
+	remaining, err := strconv.Atoi(h.Get("RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset := parseRateLimit(h).Reset
+	if !reset.After(now) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(now)
+	l.windowRate = float64(remaining) / reset.Sub(now).Seconds()
+	l.windowReset = reset
+	if l.tokens > float64(remaining) {
+		l.tokens = float64(remaining)
+	}
+}
+
+// currentRate returns the rate of l at time t, which is limited by the rate
+// announced by GitLab until the reset of its window.
+func (l *RateLimiter) currentRate(t time.Time) float64 {
+	if t.Before(l.windowReset) && l.windowRate < l.rate {
+		return l.windowRate
+	}
+	return l.rate
+}
+
+// advance adds the tokens accumulated since the last update of l.
+func (l *RateLimiter) advance(now time.Time) {
+	if !now.After(l.last) {
+		return
+	}
+
+	from := l.last
+	if from.Before(l.windowReset) {
+		end := now
+		if end.After(l.windowReset) {
+			end = l.windowReset
+		}
+		l.tokens += end.Sub(from).Seconds() * l.currentRate(from)
+		from = end
+	}
+	l.tokens += now.Sub(from).Seconds() * l.rate
+
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = now
+}
+
+// reserve takes a token for a request sent at now, and returns how long the
+// request must wait for the token to be available.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(now)
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	var wait float64
+	deficit := -l.tokens
+
+	if now.Before(l.windowReset) {
+		window := l.windowReset.Sub(now).Seconds()
+		rate := l.currentRate(now)
+		if deficit <= rate*window {
+			return seconds(deficit / rate)
+		}
+		deficit -= rate * window
+		wait = window
+	}
+	if l.rate <= 0 {
+		// Only the window of GitLab limits the requests.
+		return seconds(wait)
+	}
+
+	return seconds(wait + deficit/l.rate)
+}
+
+// seconds converts s seconds to a Duration, rounded to the nanosecond so
+// floating point errors don't shave a nanosecond off the waits.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
+
+// rateLimiters holds the limiters shared by the clients, per base URL.
+var rateLimiters = struct {
+	sync.Mutex
+	m map[string]*RateLimiter
+}{m: make(map[string]*RateLimiter)}
+
+// SetRateLimit limits the requests of c to rate requests per second on
+// average, with bursts of up to burst requests. The limiter is shared by all
+// clients using the same base URL, so set the base URL first. Calling
+// SetRateLimit again for that base URL changes the limits of all of them.
+// A rate of zero or less disables rate limiting for c, which is the default.
+func (c *Client) SetRateLimit(rate float64, burst int) {
+	if rate <= 0 {
+		c.limiter = nil
+		return
+	}
+
+	key := c.baseURL.String()
+
+	rateLimiters.Lock()
+	defer rateLimiters.Unlock()
+
+	l, ok := rateLimiters.m[key]
+	if ok {
+		l.SetLimit(rate, burst)
+	} else {
+		l = NewRateLimiter(rate, burst)
+		rateLimiters.m[key] = l
+	}
+	c.limiter = l
+}
+
+// SetRateLimiter sets the RateLimiter used to limit the requests of c, e.g.
+// to share a limiter between clients with different base URLs. A nil
+// RateLimiter disables rate limiting.
+func (c *Client) SetRateLimiter(l *RateLimiter) {
+	c.limiter = l
+}
+
+// RateLimiter returns the RateLimiter of c, or nil if its requests are not
+// limited.
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.limiter
+}
+
+// sendLimited sends req using the HTTP client of c, once the rate limiter of
+// c allows it.
+func (c *Client) sendLimited(req *http.Request) (*http.Response, error) {
+	if c.limiter == nil {
+		return c.client.Do(req)
+	}
+
+	if err := c.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err == nil {
+		c.limiter.Update(resp.Header)
+	}
+
+	return resp, err
+}
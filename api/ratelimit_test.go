@@ -0,0 +1,120 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter(2, 2)
+	l.last = now
+
+	// The burst is available at once, then requests are spaced by the rate.
+	for i, want := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if wait := l.reserve(now); wait != want {
+			t.Errorf("Request %d waits %v, want %v", i, wait, want)
+		}
+	}
+
+	// Once the reserved requests are sent, the bucket fills up again.
+	now = now.Add(10 * time.Second)
+	for i := 0; i < 2; i++ {
+		if wait := l.reserve(now); wait != 0 {
+			t.Errorf("Request %d after refill waits %v, want 0", i, wait)
+		}
+	}
+}
+
+func TestRateLimiter_update(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter(10, 10)
+	l.last = now
+
+	h := make(http.Header)
+	h.Set("RateLimit-Remaining", "2")
+	h.Set("RateLimit-Reset", strconv.FormatInt(now.Add(4*time.Second).Unix(), 10))
+	reset := time.Unix(now.Add(4*time.Second).Unix(), 0)
+	l.update(h, now)
+
+	// GitLab allows 2 more requests before the reset, which are sent at
+	// once, then the requests are spread over the rest of the window.
+	for i := 0; i < 2; i++ {
+		if wait := l.reserve(now); wait != 0 {
+			t.Errorf("Request %d waits %v, want 0", i, wait)
+		}
+	}
+	if wait, max := l.reserve(now), reset.Sub(now); wait <= 0 || wait > max {
+		t.Errorf("Request 3 waits %v, want at most %v", wait, max)
+	}
+
+	// Nothing remaining: wait for the reset, then for the rate of the client.
+	h.Set("RateLimit-Remaining", "0")
+	l = NewRateLimiter(10, 10)
+	l.last = now
+	l.update(h, now)
+
+	if wait, want := l.reserve(now), reset.Sub(now)+100*time.Millisecond; wait != want {
+		t.Errorf("Request waits %v, want %v", wait, want)
+	}
+}
+
+func TestRateLimiter_waitCancelled(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSetRateLimit(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	client.SetRateLimit(100, 10)
+
+	// Clients of the same GitLab instance share the limiter.
+	other := NewClient(nil, "")
+	other.SetBaseURL(server.URL)
+	other.SetRateLimit(50, 5)
+
+	if client.RateLimiter() != other.RateLimiter() {
+		t.Fatalf("Clients with the same base URL have different rate limiters")
+	}
+	if rate, burst := client.RateLimiter().Limit(); rate != 50 || burst != 5 {
+		t.Errorf("RateLimiter.Limit returned %v, %d, want 50, 5", rate, burst)
+	}
+
+	if _, _, err := client.Users.CurrentUser(); err != nil {
+		t.Fatalf("Users.CurrentUser returned error: %v", err)
+	}
+
+	// GitLab reported no remaining requests, so the next one has to wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := other.WithContext(ctx).Users.CurrentUser()
+	if err != context.DeadlineExceeded {
+		t.Errorf("Users.CurrentUser returned %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	client.SetRateLimit(0, 0)
+	if client.RateLimiter() != nil {
+		t.Errorf("SetRateLimit(0, 0) did not disable the rate limiter")
+	}
+}
@@ -0,0 +1,290 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ReleasesService handles communication with the release related methods of
+// the GitLab API. Releases are identified by the name of their tag.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/releases/
+type ReleasesService struct {
+	client *Client
+}
+
+// Release represents a release of a project.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/releases/
+type Release struct {
+	TagName         string  `json:"tag_name"`
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	DescriptionHTML string  `json:"description_html"`
+	CreatedAt       *Time   `json:"created_at"`
+	ReleasedAt      *Time   `json:"released_at"`
+	Author          *User   `json:"author"`
+	Commit          *Commit `json:"commit"`
+	Assets          struct {
+		Count   int `json:"count"`
+		Sources []struct {
+			Format string `json:"format"`
+			URL    string `json:"url"`
+		} `json:"sources"`
+		Links []*ReleaseLink `json:"links"`
+	} `json:"assets"`
+}
+
+func (r Release) String() string {
+	return Stringify(r)
+}
+
+// ReleaseLink represents an asset link of a release, e.g. to a binary.
+//
+// GitLab API docs: https://docs.gitlab.com/ce/api/releases/links.html
+type ReleaseLink struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	External bool   `json:"external"`
+}
+
+// ListReleasesOptions represents the available ListReleases() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/#list-releases
+type ListReleasesOptions struct {
+	ListOptions
+}
+
+// ListReleases gets a list of the releases of a project, newest first.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/#list-releases
+func (s *ReleasesService) ListReleases(
+	pid ProjectRef,
+	opt *ListReleasesOptions,
+	options ...OptionFunc) ([]*Release, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/releases", project)
+
+	req, err := s.client.NewRequest("GET", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r []*Release
+	resp, err := s.client.Do(req, &r)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return r, resp, err
+}
+
+// GetRelease gets the release of a tag.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/#get-a-release-by-a-tag-name
+func (s *ReleasesService) GetRelease(
+	pid ProjectRef,
+	tag string,
+	options ...OptionFunc) (*Release, *Response, error) {
+	return s.releaseRequest("GET", pid, tag, nil, options)
+}
+
+// ReleaseAssetsOptions represents the assets of a new release.
+type ReleaseAssetsOptions struct {
+	Links []*ReleaseLinkOptions `url:"links,omitempty" json:"links,omitempty"`
+}
+
+// CreateReleaseOptions represents the available CreateRelease() options.
+// The Ref is only used when the tag does not exist yet, to create it.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/#create-a-release
+type CreateReleaseOptions struct {
+	Name        string                `url:"name,omitempty" json:"name,omitempty"`
+	TagName     string                `url:"tag_name" json:"tag_name"`
+	Description string                `url:"description,omitempty" json:"description,omitempty"`
+	Ref         string                `url:"ref,omitempty" json:"ref,omitempty"`
+	ReleasedAt  *Time                 `url:"released_at,omitempty" json:"released_at,omitempty"`
+	Assets      *ReleaseAssetsOptions `url:"assets,omitempty" json:"assets,omitempty"`
+}
+
+// CreateRelease creates a new release, along with its asset links.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/#create-a-release
+func (s *ReleasesService) CreateRelease(
+	pid ProjectRef,
+	opt *CreateReleaseOptions,
+	options ...OptionFunc) (*Release, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/releases", project)
+
+	req, err := s.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := new(Release)
+	resp, err := s.client.Do(req, r)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return r, resp, err
+}
+
+// UpdateReleaseOptions represents the available UpdateRelease() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/#update-a-release
+type UpdateReleaseOptions struct {
+	Name        string `url:"name,omitempty" json:"name,omitempty"`
+	Description string `url:"description,omitempty" json:"description,omitempty"`
+	ReleasedAt  *Time  `url:"released_at,omitempty" json:"released_at,omitempty"`
+}
+
+// UpdateRelease updates the release of a tag. Its asset links are managed
+// with CreateReleaseLink and DeleteReleaseLink.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/#update-a-release
+func (s *ReleasesService) UpdateRelease(
+	pid ProjectRef,
+	tag string,
+	opt *UpdateReleaseOptions,
+	options ...OptionFunc) (*Release, *Response, error) {
+	return s.releaseRequest("PUT", pid, tag, opt, options)
+}
+
+// DeleteRelease deletes the release of a tag, and returns it. The tag itself
+// is kept.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/#delete-a-release
+func (s *ReleasesService) DeleteRelease(
+	pid ProjectRef,
+	tag string,
+	options ...OptionFunc) (*Release, *Response, error) {
+	return s.releaseRequest("DELETE", pid, tag, nil, options)
+}
+
+// releaseRequest sends a request to the release of a tag, and returns the
+// resulting release.
+func (s *ReleasesService) releaseRequest(
+	method string,
+	pid ProjectRef,
+	tag string,
+	opt interface{},
+	options []OptionFunc) (*Release, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/releases/%s", project, url.PathEscape(tag))
+
+	req, err := s.client.NewRequest(method, u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := new(Release)
+	resp, err := s.client.Do(req, r)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return r, resp, err
+}
+
+// ReleaseLinkOptions represents the available CreateReleaseLink() options,
+// and the asset links of CreateRelease().
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/links.html#create-a-link
+type ReleaseLinkOptions struct {
+	Name string `url:"name" json:"name"`
+	URL  string `url:"url" json:"url"`
+}
+
+// CreateReleaseLink adds an asset link to the release of a tag.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/links.html#create-a-link
+func (s *ReleasesService) CreateReleaseLink(
+	pid ProjectRef,
+	tag string,
+	opt *ReleaseLinkOptions,
+	options ...OptionFunc) (*ReleaseLink, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/releases/%s/assets/links", project, url.PathEscape(tag))
+
+	req, err := s.client.NewRequest("POST", u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l := new(ReleaseLink)
+	resp, err := s.client.Do(req, l)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return l, resp, err
+}
+
+// DeleteReleaseLink deletes an asset link of the release of a tag.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/releases/links.html#delete-a-link
+func (s *ReleasesService) DeleteReleaseLink(
+	pid ProjectRef,
+	tag string,
+	link int,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("projects/%s/releases/%s/assets/links/%d", project, url.PathEscape(tag), link)
+
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
@@ -0,0 +1,108 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListReleases(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/releases", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"tag_name":"v1.0","name":"Awesome app v1.0","assets":{"count":1,"links":[{"id":2,"name":"linux-amd64","url":"https://example.com/app"}]}}]`)
+	})
+
+	releases, _, err := client.Releases.ListReleases(ProjectID(1), nil)
+	if err != nil {
+		t.Fatalf("Releases.ListReleases returned error: %v", err)
+	}
+
+	if len(releases) != 1 || len(releases[0].Assets.Links) != 1 || releases[0].Assets.Links[0].Name != "linux-amd64" {
+		t.Errorf("Releases.ListReleases returned %+v", releases)
+	}
+}
+
+func TestCreateRelease(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/releases", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"name":"v1.0","tag_name":"v1.0","description":"First","ref":"master","assets":{"links":[{"name":"linux-amd64","url":"https://example.com/app"}]}}`)
+		fmt.Fprint(w, `{"tag_name":"v1.0","name":"v1.0","description":"First"}`)
+	})
+
+	opt := &CreateReleaseOptions{
+		Name:        "v1.0",
+		TagName:     "v1.0",
+		Description: "First",
+		Ref:         "master",
+		Assets: &ReleaseAssetsOptions{
+			Links: []*ReleaseLinkOptions{{Name: "linux-amd64", URL: "https://example.com/app"}},
+		},
+	}
+	release, _, err := client.Releases.CreateRelease(ProjectID(1), opt)
+	if err != nil {
+		t.Fatalf("Releases.CreateRelease returned error: %v", err)
+	}
+
+	if release.TagName != "v1.0" {
+		t.Errorf("Releases.CreateRelease returned %+v", release)
+	}
+}
+
+func TestUpdateAndDeleteRelease(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/releases/", func(w http.ResponseWriter, r *http.Request) {
+		testUrl(t, r, "/projects/1/releases/release%2Fv1.0")
+		switch r.Method {
+		case "PUT":
+			testBody(t, r, `{"description":"Updated"}`)
+			fmt.Fprint(w, `{"tag_name":"release/v1.0","description":"Updated"}`)
+		case "DELETE":
+			fmt.Fprint(w, `{"tag_name":"release/v1.0"}`)
+		default:
+			t.Errorf("Request method: %s, want PUT or DELETE", r.Method)
+		}
+	})
+
+	release, _, err := client.Releases.UpdateRelease(ProjectID(1), "release/v1.0", &UpdateReleaseOptions{Description: "Updated"})
+	if err != nil || release.Description != "Updated" {
+		t.Errorf("Releases.UpdateRelease returned %+v, %v", release, err)
+	}
+
+	release, _, err = client.Releases.DeleteRelease(ProjectID(1), "release/v1.0")
+	if err != nil || release.TagName != "release/v1.0" {
+		t.Errorf("Releases.DeleteRelease returned %+v, %v", release, err)
+	}
+}
+
+func TestReleaseLinks(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/releases/v1.0/assets/links", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testBody(t, r, `{"name":"checksums","url":"https://example.com/sha256"}`)
+		fmt.Fprint(w, `{"id":3,"name":"checksums","url":"https://example.com/sha256","external":true}`)
+	})
+	mux.HandleFunc("/projects/1/releases/v1.0/assets/links/3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		fmt.Fprint(w, `{"id":3}`)
+	})
+
+	opt := &ReleaseLinkOptions{Name: "checksums", URL: "https://example.com/sha256"}
+	link, _, err := client.Releases.CreateReleaseLink(ProjectID(1), "v1.0", opt)
+	if err != nil || link.ID != 3 || !link.External {
+		t.Errorf("Releases.CreateReleaseLink returned %+v, %v", link, err)
+	}
+
+	if _, err := client.Releases.DeleteReleaseLink(ProjectID(1), "v1.0", 3); err != nil {
+		t.Errorf("Releases.DeleteReleaseLink returned error: %v", err)
+	}
+}
@@ -19,6 +19,7 @@ package gitlab
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/url"
 )
 
@@ -35,28 +36,40 @@ type RepositoriesService struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#list-project-repository-tags
 type Tag struct {
-	Commit  *Commit `json:"commit"`
-	Name    string  `json:"name"`
-	Message string  `json:"message"`
+	Commit  *Commit      `json:"commit"`
+	Release *ReleaseNote `json:"release"`
+	Name    string       `json:"name"`
+	Message string       `json:"message"`
 }
 
 func (r Tag) String() string {
 	return Stringify(r)
 }
 
+// ListTagsOptions represents the available ListTags() options.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/repositories.html#list-project-repository-tags
+type ListTagsOptions struct {
+	ListOptions
+}
+
 // ListTags gets a list of repository tags from a project, sorted by name in
 // reverse alphabetical order.
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#list-project-repository-tags
-func (s *RepositoriesService) ListTags(pid interface{}) ([]*Tag, *Response, error) {
-	project, err := parseID(pid)
+func (s *RepositoriesService) ListTags(
+	pid ProjectRef,
+	opt *ListTagsOptions,
+	options ...OptionFunc) ([]*Tag, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/tags", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/tags", project)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -85,15 +98,16 @@ type CreateTagOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#create-a-new-tag
 func (s *RepositoriesService) CreateTag(
-	pid interface{},
-	opt *CreateTagOptions) (*Tag, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CreateTagOptions,
+	options ...OptionFunc) (*Tag, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/tags", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/tags", project)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -107,6 +121,131 @@ func (s *RepositoriesService) CreateTag(
 	return t, resp, err
 }
 
+// GetTag gets a single repository tag of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/tags.html#get-a-single-repository-tag
+func (s *RepositoriesService) GetTag(
+	pid ProjectRef,
+	tag string,
+	options ...OptionFunc) (*Tag, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/repository/tags/%s", project, url.PathEscape(tag))
+
+	req, err := s.client.NewRequest("GET", u, nil, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := new(Tag)
+	resp, err := s.client.Do(req, t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return t, resp, err
+}
+
+// DeleteTag deletes a repository tag of a project.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/tags.html#delete-a-tag
+func (s *RepositoriesService) DeleteTag(
+	pid ProjectRef,
+	tag string,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("projects/%s/repository/tags/%s", project, url.PathEscape(tag))
+
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, err
+}
+
+// ReleaseNote represents the release notes of a tag.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/tags.html#create-a-new-release
+type ReleaseNote struct {
+	TagName     string `json:"tag_name"`
+	Description string `json:"description"`
+}
+
+// ReleaseNoteOptions represents the available CreateReleaseNote() and
+// UpdateReleaseNote() options.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/tags.html#create-a-new-release
+type ReleaseNoteOptions struct {
+	Description string `url:"description" json:"description"`
+}
+
+// CreateReleaseNote adds release notes to an existing tag. The description
+// is Markdown.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/tags.html#create-a-new-release
+func (s *RepositoriesService) CreateReleaseNote(
+	pid ProjectRef,
+	tag string,
+	opt *ReleaseNoteOptions,
+	options ...OptionFunc) (*ReleaseNote, *Response, error) {
+	return s.releaseNoteRequest("POST", pid, tag, opt, options)
+}
+
+// UpdateReleaseNote updates the release notes of a tag.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ce/api/tags.html#update-a-release
+func (s *RepositoriesService) UpdateReleaseNote(
+	pid ProjectRef,
+	tag string,
+	opt *ReleaseNoteOptions,
+	options ...OptionFunc) (*ReleaseNote, *Response, error) {
+	return s.releaseNoteRequest("PUT", pid, tag, opt, options)
+}
+
+// releaseNoteRequest sends the release notes of a tag with the given method.
+func (s *RepositoriesService) releaseNoteRequest(
+	method string,
+	pid ProjectRef,
+	tag string,
+	opt *ReleaseNoteOptions,
+	options []OptionFunc) (*ReleaseNote, *Response, error) {
+	project, err := parseProject(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("projects/%s/repository/tags/%s/release", project, url.PathEscape(tag))
+
+	req, err := s.client.NewRequest(method, u, opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := new(ReleaseNote)
+	resp, err := s.client.Do(req, r)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return r, resp, err
+}
+
 // TreeNode represents a GitLab repository file or directory.
 //
 // GitLab API docs:
@@ -127,6 +266,7 @@ func (t TreeNode) String() string {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#list-repository-tree
 type ListTreeOptions struct {
+	ListOptions
 	Path    string `url:"path,omitempty" json:"path,omitempty"`
 	RefName string `url:"ref_name,omitempty" json:"ref_name,omitempty"`
 }
@@ -136,15 +276,16 @@ type ListTreeOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#list-repository-tree
 func (s *RepositoriesService) ListTree(
-	pid interface{},
-	opt *ListTreeOptions) ([]*TreeNode, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ListTreeOptions,
+	options ...OptionFunc) ([]*TreeNode, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/tree", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/tree", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -171,27 +312,81 @@ type RawFileContentOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#raw-file-content
 func (s *RepositoriesService) RawFileContent(
-	pid interface{},
+	pid ProjectRef,
 	sha string,
-	opt *RawFileContentOptions) ([]byte, *Response, error) {
-	project, err := parseID(pid)
+	opt *RawFileContentOptions,
+	options ...OptionFunc) ([]byte, *Response, error) {
+	var b bytes.Buffer
+	resp, err := s.StreamRawFileContent(pid, sha, opt, &b, options...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return b.Bytes(), resp, err
+}
+
+// StreamRawFileContent is like RawFileContent, but writes the contents to w
+// as they are received.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/repositories.html#raw-file-content
+func (s *RepositoriesService) StreamRawFileContent(
+	pid ProjectRef,
+	sha string,
+	opt *RawFileContentOptions,
+	w io.Writer,
+	options ...OptionFunc) (*Response, error) {
+	body, resp, err := s.RawFileContentReader(pid, sha, opt, options...)
+	if err != nil {
+		return resp, err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+	return resp, err
+}
+
+// RawFileContentReader is like RawFileContent, but returns the contents as a
+// stream, which the caller must close.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/repositories.html#raw-file-content
+func (s *RepositoriesService) RawFileContentReader(
+	pid ProjectRef,
+	sha string,
+	opt *RawFileContentOptions,
+	options ...OptionFunc) (io.ReadCloser, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/blobs/%s", url.QueryEscape(project), sha)
+	u, o := fmt.Sprintf("projects/%s/repository/blobs/%s", project, sha), interface{}(opt)
+	if s.client.apiVersion != APIVersion3 {
+		if opt != nil && opt.FilePath != "" {
+			// API v4 serves raw files at repository/files/:file_path/raw.
+			u = fmt.Sprintf("projects/%s/repository/files/%s/raw",
+				project, url.PathEscape(opt.FilePath))
+			o = &struct {
+				Ref string `url:"ref"`
+			}{sha}
+		} else {
+			// Without a path, sha is a blob, whose metadata API v4 serves
+			// at repository/blobs/:sha.
+			u, o = fmt.Sprintf("projects/%s/repository/blobs/%s/raw", project, sha), nil
+		}
+	}
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, o, options...)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var b bytes.Buffer
-	resp, err := s.client.Do(req, &b)
+	resp, err := s.client.doStream(req)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return b.Bytes(), resp, err
+	return resp.Body, resp, err
 }
 
 // RawBlobContent gets the raw file contents for a blob by blob SHA.
@@ -199,26 +394,67 @@ func (s *RepositoriesService) RawFileContent(
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#raw-blob-content
 func (s *RepositoriesService) RawBlobContent(
-	pid interface{},
-	sha string) ([]byte, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	sha string,
+	options ...OptionFunc) ([]byte, *Response, error) {
+	var b bytes.Buffer
+	resp, err := s.StreamRawBlobContent(pid, sha, &b, options...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return b.Bytes(), resp, err
+}
+
+// StreamRawBlobContent is like RawBlobContent, but writes the contents to w
+// as they are received.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/repositories.html#raw-blob-content
+func (s *RepositoriesService) StreamRawBlobContent(
+	pid ProjectRef,
+	sha string,
+	w io.Writer,
+	options ...OptionFunc) (*Response, error) {
+	body, resp, err := s.RawBlobContentReader(pid, sha, options...)
+	if err != nil {
+		return resp, err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+	return resp, err
+}
+
+// RawBlobContentReader is like RawBlobContent, but returns the contents as a
+// stream, which the caller must close.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/repositories.html#raw-blob-content
+func (s *RepositoriesService) RawBlobContentReader(
+	pid ProjectRef,
+	sha string,
+	options ...OptionFunc) (io.ReadCloser, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/raw_blobs/%s", url.QueryEscape(project), sha)
+	u := fmt.Sprintf("projects/%s/repository/blobs/%s/raw", project, sha)
+	if s.client.apiVersion == APIVersion3 {
+		u = fmt.Sprintf("projects/%s/repository/raw_blobs/%s", project, sha)
+	}
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var b bytes.Buffer
-	resp, err := s.client.Do(req, &b)
+	resp, err := s.client.doStream(req)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return b.Bytes(), resp, err
+	return resp.Body, resp, err
 }
 
 // ArchiveOptions represents the available Archive() options.
@@ -227,6 +463,9 @@ func (s *RepositoriesService) RawBlobContent(
 // http://doc.gitlab.com/ce/api/repositories.html#get-file-archive
 type ArchiveOptions struct {
 	SHA string `url:"sha,omitempty" json:"sha,omitempty"`
+
+	// The format of the archive: tar.gz (the default), tar.bz2, tar or zip.
+	Format string `url:"-" json:"-"`
 }
 
 // Archive gets an archive of the repository.
@@ -234,26 +473,67 @@ type ArchiveOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#get-file-archive
 func (s *RepositoriesService) Archive(
-	pid interface{},
-	opt *ArchiveOptions) ([]byte, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *ArchiveOptions,
+	options ...OptionFunc) ([]byte, *Response, error) {
+	var b bytes.Buffer
+	resp, err := s.StreamArchive(pid, opt, &b, options...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return b.Bytes(), resp, err
+}
+
+// StreamArchive is like Archive, but writes the archive to w as it is
+// received, so large archives are not kept in memory.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/repositories.html#get-file-archive
+func (s *RepositoriesService) StreamArchive(
+	pid ProjectRef,
+	opt *ArchiveOptions,
+	w io.Writer,
+	options ...OptionFunc) (*Response, error) {
+	body, resp, err := s.ArchiveReader(pid, opt, options...)
+	if err != nil {
+		return resp, err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+	return resp, err
+}
+
+// ArchiveReader is like Archive, but returns the archive as a stream, which
+// the caller must close.
+//
+// GitLab API docs:
+// http://doc.gitlab.com/ce/api/repositories.html#get-file-archive
+func (s *RepositoriesService) ArchiveReader(
+	pid ProjectRef,
+	opt *ArchiveOptions,
+	options ...OptionFunc) (io.ReadCloser, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/archive", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/archive", project)
+	if opt != nil && opt.Format != "" {
+		u += "." + opt.Format
+	}
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var b bytes.Buffer
-	resp, err := s.client.Do(req, &b)
+	resp, err := s.client.doStream(req)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return b.Bytes(), resp, err
+	return resp.Body, resp, err
 }
 
 // Compare represents the result of a comparison of branches, tags or commits.
@@ -286,15 +566,16 @@ type CompareOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repositories.html#compare-branches-tags-or-commits
 func (s *RepositoriesService) Compare(
-	pid interface{},
-	opt *CompareOptions) (*Compare, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CompareOptions,
+	options ...OptionFunc) (*Compare, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/compare", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/compare", project)
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -326,14 +607,16 @@ func (c Contributor) String() string {
 // Contributors gets the repository contributors list.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/repositories.html#contributer
-func (s *RepositoriesService) Contributors(pid interface{}) ([]*Contributor, *Response, error) {
-	project, err := parseID(pid)
+func (s *RepositoriesService) Contributors(
+	pid ProjectRef,
+	options ...OptionFunc) ([]*Contributor, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/contributors", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/contributors", project)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
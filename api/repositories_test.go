@@ -0,0 +1,196 @@
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/archive", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"sha": "master"})
+		fmt.Fprint(w, "tar.gz")
+	})
+
+	archive, _, err := client.Repositories.Archive(ProjectID(1), &ArchiveOptions{SHA: "master"})
+	if err != nil {
+		t.Fatalf("Repositories.Archive returned error: %v", err)
+	}
+	if string(archive) != "tar.gz" {
+		t.Errorf("Repositories.Archive returned %q, want %q", archive, "tar.gz")
+	}
+}
+
+func TestStreamArchive_format(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	content := strings.Repeat("zip", 1<<16)
+	mux.HandleFunc("/projects/1/repository/archive.zip", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, content)
+	})
+
+	var b bytes.Buffer
+	_, err := client.Repositories.StreamArchive(ProjectID(1), &ArchiveOptions{Format: "zip"}, &b)
+	if err != nil {
+		t.Fatalf("Repositories.StreamArchive returned error: %v", err)
+	}
+	if b.String() != content {
+		t.Errorf("Repositories.StreamArchive wrote %d bytes, want %d", b.Len(), len(content))
+	}
+}
+
+func TestArchiveReader_error(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/archive", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"404 Project Not Found"}`, http.StatusNotFound)
+	})
+
+	body, _, err := client.Repositories.ArchiveReader(ProjectID(1), nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Repositories.ArchiveReader returned error %v, want ErrNotFound", err)
+	}
+	if body != nil {
+		t.Errorf("Repositories.ArchiveReader returned a body on error")
+	}
+}
+
+func TestRawBlobContentReader(t *testing.T) {
+	for _, v := range []struct {
+		version APIVersion
+		path    string
+	}{
+		{APIVersion3, "/projects/1/repository/raw_blobs/abc"},
+		{APIVersion4, "/projects/1/repository/blobs/abc/raw"},
+	} {
+		mux, server, client := setup()
+		client.SetAPIVersion(v.version)
+
+		mux.HandleFunc(v.path, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			fmt.Fprint(w, "blob")
+		})
+
+		body, _, err := client.Repositories.RawBlobContentReader(ProjectID(1), "abc")
+		if err != nil {
+			t.Fatalf("Repositories.RawBlobContentReader (%s) returned error: %v", v.version, err)
+		}
+		b, _ := ioutil.ReadAll(body)
+		body.Close()
+
+		if string(b) != "blob" {
+			t.Errorf("Repositories.RawBlobContentReader (%s) returned %q, want %q", v.version, b, "blob")
+		}
+
+		teardown(server)
+	}
+}
+
+func TestRawFileContent_v4(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/files/", func(w http.ResponseWriter, r *http.Request) {
+		testUrl(t, r, "/projects/1/repository/files/app%2Fmain.go/raw?ref=abc")
+		fmt.Fprint(w, "package main")
+	})
+
+	b, _, err := client.Repositories.RawFileContent(ProjectID(1), "abc", &RawFileContentOptions{FilePath: "app/main.go"})
+	if err != nil {
+		t.Fatalf("Repositories.RawFileContent returned error: %v", err)
+	}
+	if string(b) != "package main" {
+		t.Errorf("Repositories.RawFileContent returned %q, want %q", b, "package main")
+	}
+}
+
+func TestRawFileContent_v4NoPath(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/blobs/abc/raw", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testUrl(t, r, "/projects/1/repository/blobs/abc/raw")
+		fmt.Fprint(w, "package main")
+	})
+
+	b, _, err := client.Repositories.RawFileContent(ProjectID(1), "abc", nil)
+	if err != nil {
+		t.Fatalf("Repositories.RawFileContent returned error: %v", err)
+	}
+	if string(b) != "package main" {
+		t.Errorf("Repositories.RawFileContent returned %q, want %q", b, "package main")
+	}
+}
+
+func TestGetTag(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/tags/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testUrl(t, r, "/projects/1/repository/tags/release%2Fv1.0")
+		fmt.Fprint(w, `{"name":"release/v1.0","commit":{"id":"2695effb"},"release":{"tag_name":"release/v1.0","description":"Amazing release"}}`)
+	})
+
+	tag, _, err := client.Repositories.GetTag(ProjectID(1), "release/v1.0")
+	if err != nil {
+		t.Fatalf("Repositories.GetTag returned error: %v", err)
+	}
+
+	if tag.Release == nil || tag.Release.Description != "Amazing release" {
+		t.Errorf("Repositories.GetTag returned %+v", tag)
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/tags/v1.0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.Repositories.DeleteTag(ProjectID(1), "v1.0"); err != nil {
+		t.Errorf("Repositories.DeleteTag returned error: %v", err)
+	}
+}
+
+func TestReleaseNote(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/tags/v1.0/release", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" && r.Method != "PUT" {
+			t.Errorf("Request method: %s, want POST or PUT", r.Method)
+		}
+		var opt ReleaseNoteOptions
+		if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+			t.Errorf("Error decoding request body: %v", err)
+		}
+		fmt.Fprintf(w, `{"tag_name":"v1.0","description":%q}`, opt.Description)
+	})
+
+	note, _, err := client.Repositories.CreateReleaseNote(ProjectID(1), "v1.0", &ReleaseNoteOptions{Description: "First"})
+	if err != nil || note.Description != "First" {
+		t.Errorf("Repositories.CreateReleaseNote returned %+v, %v", note, err)
+	}
+
+	note, _, err = client.Repositories.UpdateReleaseNote(ProjectID(1), "v1.0", &ReleaseNoteOptions{Description: "Second"})
+	if err != nil || note.Description != "Second" {
+		t.Errorf("Repositories.UpdateReleaseNote returned %+v, %v", note, err)
+	}
+}
@@ -22,7 +22,9 @@ import (
 )
 
 // RepositoryFilesService handles communication with the repository files
-// related methods of the GitLab API.
+// related methods of the GitLab API. API v4 expects the path of the file in
+// the URL rather than in the parameters, and renamed branch_name to branch;
+// the methods take care of this, so either BranchName or Branch can be used.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/repository_files.html
 type RepositoryFilesService struct {
@@ -62,15 +64,22 @@ type GetFileOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repository_files.html#get-file-from-respository
 func (s *RepositoryFilesService) GetFile(
-	pid interface{},
-	opt *GetFileOptions) (*File, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *GetFileOptions,
+	options ...OptionFunc) (*File, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/files", url.QueryEscape(project))
+	u, o := fmt.Sprintf("projects/%s/repository/files", project), interface{}(opt)
+	if s.client.apiVersion != APIVersion3 && opt != nil {
+		u = s.filePath(project, opt.FilePath)
+		o = &struct {
+			Ref string `url:"ref,omitempty"`
+		}{opt.Ref}
+	}
 
-	req, err := s.client.NewRequest("GET", u, opt)
+	req, err := s.client.NewRequest("GET", u, o, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -90,6 +99,7 @@ func (s *RepositoryFilesService) GetFile(
 type FileInfo struct {
 	FilePath   string `json:"file_path"`
 	BranchName string `json:"branch_name"`
+	Branch     string `json:"branch"`
 }
 
 func (r FileInfo) String() string {
@@ -103,6 +113,7 @@ func (r FileInfo) String() string {
 type CreateFileOptions struct {
 	FilePath      string `url:"file_path,omitempty" json:"file_path,omitempty"`
 	BranchName    string `url:"branch_name,omitempty" json:"branch_name,omitempty"`
+	Branch        string `url:"branch,omitempty" json:"branch,omitempty"`
 	Encoding      string `url:"encoding,omitempty" json:"encoding,omitempty"`
 	Content       string `url:"content,omitempty" json:"content,omitempty"`
 	CommitMessage string `url:"commit_message,omitempty" json:"commit_message,omitempty"`
@@ -113,15 +124,24 @@ type CreateFileOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repository_files.html#create-new-file-in-repository
 func (s *RepositoryFilesService) CreateFile(
-	pid interface{},
-	opt *CreateFileOptions) (*FileInfo, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *CreateFileOptions,
+	options ...OptionFunc) (*FileInfo, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/files", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/files", project)
+	if s.client.apiVersion != APIVersion3 && opt != nil {
+		u = s.filePath(project, opt.FilePath)
+		o := *opt
+		if o.Branch == "" {
+			o.Branch, o.BranchName = o.BranchName, ""
+		}
+		opt = &o
+	}
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -142,6 +162,7 @@ func (s *RepositoryFilesService) CreateFile(
 type UpdateFileOptions struct {
 	FilePath      string `url:"file_path,omitempty" json:"file_path,omitempty"`
 	BranchName    string `url:"branch_name,omitempty" json:"branch_name,omitempty"`
+	Branch        string `url:"branch,omitempty" json:"branch,omitempty"`
 	Encoding      string `url:"encoding,omitempty" json:"encoding,omitempty"`
 	Content       string `url:"content,omitempty" json:"content,omitempty"`
 	CommitMessage string `url:"commit_message,omitempty" json:"commit_message,omitempty"`
@@ -152,15 +173,24 @@ type UpdateFileOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repository_files.html#update-existing-file-in-repository
 func (s *RepositoryFilesService) UpdateFile(
-	pid interface{},
-	opt *UpdateFileOptions) (*FileInfo, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *UpdateFileOptions,
+	options ...OptionFunc) (*FileInfo, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/files", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/files", project)
+	if s.client.apiVersion != APIVersion3 && opt != nil {
+		u = s.filePath(project, opt.FilePath)
+		o := *opt
+		if o.Branch == "" {
+			o.Branch, o.BranchName = o.BranchName, ""
+		}
+		opt = &o
+	}
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -181,6 +211,7 @@ func (s *RepositoryFilesService) UpdateFile(
 type DeleteFileOptions struct {
 	FilePath      string `url:"file_path,omitempty" json:"file_path,omitempty"`
 	BranchName    string `url:"branch_name,omitempty" json:"branch_name,omitempty"`
+	Branch        string `url:"branch,omitempty" json:"branch,omitempty"`
 	CommitMessage string `url:"commit_message,omitempty" json:"commit_message,omitempty"`
 }
 
@@ -189,15 +220,24 @@ type DeleteFileOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/repository_files.html#delete-existing-file-in-repository
 func (s *RepositoryFilesService) DeleteFile(
-	pid interface{},
-	opt *DeleteFileOptions) (*FileInfo, *Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *DeleteFileOptions,
+	options ...OptionFunc) (*FileInfo, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/repository/files", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/repository/files", project)
+	if s.client.apiVersion != APIVersion3 && opt != nil {
+		u = s.filePath(project, opt.FilePath)
+		o := *opt
+		if o.Branch == "" {
+			o.Branch, o.BranchName = o.BranchName, ""
+		}
+		opt = &o
+	}
 
-	req, err := s.client.NewRequest("DELETE", u, opt)
+	req, err := s.client.NewRequest("DELETE", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -210,3 +250,8 @@ func (s *RepositoryFilesService) DeleteFile(
 
 	return f, resp, err
 }
+
+// filePath returns the API v4 path of a single file.
+func (s *RepositoryFilesService) filePath(project, file string) string {
+	return fmt.Sprintf("projects/%s/repository/files/%s", project, url.PathEscape(file))
+}
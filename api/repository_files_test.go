@@ -0,0 +1,91 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetFile_v3(t *testing.T) {
+	mux, server, client := setupV3()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/files", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"file_path": "app/models/key.rb",
+			"ref":       "master",
+		})
+		fmt.Fprint(w, `{"file_path":"app/models/key.rb","ref":"master"}`)
+	})
+
+	opt := &GetFileOptions{FilePath: "app/models/key.rb", Ref: "master"}
+	file, _, err := client.RepositoryFiles.GetFile(ProjectID(1), opt)
+	if err != nil {
+		t.Errorf("RepositoryFiles.GetFile returned error: %v", err)
+	}
+
+	want := &File{FilePath: "app/models/key.rb", Ref: "master"}
+	if !reflect.DeepEqual(want, file) {
+		t.Errorf("RepositoryFiles.GetFile returned %+v, want %+v", file, want)
+	}
+}
+
+func TestGetFile_v4(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/files/", func(w http.ResponseWriter, r *http.Request) {
+		testUrl(t, r, "/projects/1/repository/files/app%2Fmodels%2Fkey.rb?ref=master")
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"file_path":"app/models/key.rb","ref":"master"}`)
+	})
+
+	opt := &GetFileOptions{FilePath: "app/models/key.rb", Ref: "master"}
+	file, _, err := client.RepositoryFiles.GetFile(ProjectID(1), opt)
+	if err != nil {
+		t.Errorf("RepositoryFiles.GetFile returned error: %v", err)
+	}
+
+	want := &File{FilePath: "app/models/key.rb", Ref: "master"}
+	if !reflect.DeepEqual(want, file) {
+		t.Errorf("RepositoryFiles.GetFile returned %+v, want %+v", file, want)
+	}
+}
+
+func TestCreateFile_v4(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/repository/files/", func(w http.ResponseWriter, r *http.Request) {
+		testUrl(t, r, "/projects/1/repository/files/app%2Fproject.rb")
+		testMethod(t, r, "POST")
+		testJsonBody(t, r, values{
+			"file_path":      "app/project.rb",
+			"branch":         "master",
+			"content":        "some content",
+			"commit_message": "create a new file",
+		})
+		fmt.Fprint(w, `{"file_path":"app/project.rb","branch":"master"}`)
+	})
+
+	opt := &CreateFileOptions{
+		FilePath:      "app/project.rb",
+		BranchName:    "master",
+		Content:       "some content",
+		CommitMessage: "create a new file",
+	}
+	info, _, err := client.RepositoryFiles.CreateFile(ProjectID(1), opt)
+	if err != nil {
+		t.Errorf("RepositoryFiles.CreateFile returned error: %v", err)
+	}
+
+	want := &FileInfo{FilePath: "app/project.rb", Branch: "master"}
+	if !reflect.DeepEqual(want, info) {
+		t.Errorf("RepositoryFiles.CreateFile returned %+v, want %+v", info, want)
+	}
+	if opt.BranchName != "master" || opt.Branch != "" {
+		t.Errorf("RepositoryFiles.CreateFile modified its options: %+v", opt)
+	}
+}
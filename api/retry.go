@@ -0,0 +1,273 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes if and how failed requests are retried.
+//
+// Requests are retried when sending them fails, when GitLab throttles them
+// (429 Too Many Requests) or when GitLab is temporarily unavailable (500, 502,
+// 503 and 504). Requests that are not idempotent (POST and PATCH) are only
+// retried when GitLab throttled them, as in that case GitLab did not process
+// the request, unless RetryNonIdempotent is set. GitLab does not deduplicate
+// requests, so an Idempotency-Key header does not make them safe to retry.
+type RetryPolicy struct {
+	// The maximum number of retries. Zero disables retrying.
+	MaxRetries int
+
+	// The backoff before the first retry. It is doubled for every subsequent
+	// retry, up to MaxBackoff, and a random jitter is applied to it.
+	MinBackoff time.Duration
+
+	// The maximum backoff between two retries.
+	MaxBackoff time.Duration
+
+	// The maximum time to wait when GitLab asks us to come back later using
+	// the Retry-After or RateLimit-Reset headers. If GitLab asks for a longer
+	// wait, the request is not retried. Zero means no maximum.
+	MaxWait time.Duration
+
+	// Also retry non-idempotent requests after network errors and server
+	// errors. Only enable this when replaying a request is harmless.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with sensible defaults: at most 3
+// retries, backing off from half a second to 30 seconds, and waiting at most a
+// minute for rate limits to reset.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+		MaxWait:    time.Minute,
+	}
+}
+
+// SetRetryPolicy sets the policy used to retry failed requests. A nil policy
+// disables retrying, which is the default.
+func (c *Client) SetRetryPolicy(p *RetryPolicy) {
+	c.retry = p
+}
+
+// RetryPolicy returns the retry policy of the Client, or nil if requests are
+// not retried.
+func (c *Client) RetryPolicy() *RetryPolicy {
+	return c.retry
+}
+
+// send sends req using the HTTP client of c, retrying it according to the
+// retry policy of c. Every attempt waits for the rate limiter of c, and is
+// logged by the logger of c.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	resp, err := c.sendLogged(req)
+
+	for attempt := 0; ; attempt++ {
+		wait, retry := c.retry.backoff(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		next, rerr := rewindRequest(req)
+		if rerr != nil {
+			return resp, err
+		}
+		// Credentials may have expired while waiting, e.g. OAuth tokens.
+		if aerr := c.authenticate(next); aerr != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			// Drain the body so the connection can be reused.
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+
+		req = next
+		resp, err = c.sendLogged(req)
+	}
+}
+
+// backoff reports whether a request that got resp and err after attempt
+// retries should be retried, and how long to wait before doing so.
+func (p *RetryPolicy) backoff(
+	req *http.Request,
+	resp *http.Response,
+	err error,
+	attempt int) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxRetries {
+		return 0, false
+	}
+
+	// Never retry when the caller gave up.
+	if req.Context().Err() != nil {
+		return 0, false
+	}
+
+	if err != nil {
+		return p.exponential(attempt), p.replayable(req)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// The request was throttled before being processed, so it is safe
+		// to replay it whatever its method.
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !p.replayable(req) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if wait, ok := retryAfter(resp); ok {
+		if p.MaxWait > 0 && wait > p.MaxWait {
+			return 0, false
+		}
+		return wait, true
+	}
+
+	return p.exponential(attempt), true
+}
+
+// exponential returns the exponential backoff for the given attempt, with a
+// random jitter of up to half its value.
+func (p *RetryPolicy) exponential(attempt int) time.Duration {
+	d := p.MinBackoff
+	for i := 0; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// replayable reports whether req may be sent again after a failure of which
+// we do not know if GitLab processed the request or not.
+func (p *RetryPolicy) replayable(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+// retryAfter returns how long GitLab asked us to wait before sending another
+// request, based on the Retry-After and RateLimit-Reset headers of resp.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return nonNegative(time.Until(t)), true
+		}
+	}
+
+	if rl := parseRateLimit(resp.Header); !rl.Reset.IsZero() && rl.Remaining == 0 {
+		return nonNegative(time.Until(rl.Reset)), true
+	}
+
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+var errNotRewindable = errors.New("request body cannot be rewound")
+
+// rewindRequest returns a copy of req with a fresh body, so it can be sent
+// again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, errNotRewindable
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+
+	return next, nil
+}
+
+// RateLimit represents the rate limit status of the authenticated user, as
+// reported by GitLab in the RateLimit-* response headers.
+//
+// GitLab API docs:
+// https://docs.gitlab.com/ee/user/admin_area/settings/user_and_ip_rate_limits.html#response-headers
+type RateLimit struct {
+	// The number of requests allowed in the current window.
+	Limit int
+
+	// The number of requests made in the current window.
+	Observed int
+
+	// The number of requests left in the current window.
+	Remaining int
+
+	// The time at which the current window resets.
+	Reset time.Time
+}
+
+// parseRateLimit parses the RateLimit-* headers in h. Missing headers leave
+// the corresponding fields at their zero value.
+func parseRateLimit(h http.Header) RateLimit {
+	var rl RateLimit
+	rl.Limit, _ = strconv.Atoi(h.Get("RateLimit-Limit"))
+	rl.Observed, _ = strconv.Atoi(h.Get("RateLimit-Observed"))
+	rl.Remaining, _ = strconv.Atoi(h.Get("RateLimit-Remaining"))
+	if v := h.Get("RateLimit-Reset"); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			rl.Reset = time.Unix(secs, 0)
+		}
+	} else if v := h.Get("RateLimit-ResetTime"); v != "" {
+		rl.Reset, _ = http.ParseTime(v)
+	}
+	return rl
+}
@@ -0,0 +1,171 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+		MaxWait:    time.Second,
+	}
+}
+
+func TestDo_retryServerErrors(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/projects/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	project, _, err := client.Projects.GetProject(ProjectID(1))
+	if err != nil {
+		t.Fatalf("Projects.GetProject returned error: %v", err)
+	}
+	if *project.ID != 1 {
+		t.Errorf("Projects.GetProject returned %+v, want ID 1", project)
+	}
+	if attempts != 3 {
+		t.Errorf("Projects.GetProject made %d attempts, want 3", attempts)
+	}
+}
+
+func TestDo_retryGivesUp(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/projects/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, resp, err := client.Projects.GetProject(ProjectID(1))
+	if _, ok := err.(*ErrorResponse); !ok {
+		t.Errorf("Projects.GetProject returned error %v, want *ErrorResponse", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Projects.GetProject returned response %v, want status 502", resp)
+	}
+	if attempts != 4 {
+		t.Errorf("Projects.GetProject made %d attempts, want 4", attempts)
+	}
+}
+
+func TestDo_noRetryNonIdempotent(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/projects/1/issues/1/notes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	// GitLab ignores idempotency keys, so they don't make POSTs replayable.
+	opt := &CreateIssueNoteOptions{Body: "b"}
+	if _, _, err := client.Notes.CreateIssueNote(ProjectID(1), 1, opt, WithIdempotencyKey("key")); err == nil {
+		t.Errorf("Notes.CreateIssueNote returned no error")
+	}
+	if attempts != 1 {
+		t.Errorf("Notes.CreateIssueNote made %d attempts, want 1", attempts)
+	}
+}
+
+func TestDo_retryThrottledPost(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/projects/1/issues/1/notes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testJsonBody(t, r, values{"body": "b"})
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	opt := &CreateIssueNoteOptions{Body: "b"}
+	note, _, err := client.Notes.CreateIssueNote(ProjectID(1), 1, opt)
+	if err != nil {
+		t.Fatalf("Notes.CreateIssueNote returned error: %v", err)
+	}
+	if note.ID != 1 {
+		t.Errorf("Notes.CreateIssueNote returned %+v, want ID 1", note)
+	}
+	if attempts != 2 {
+		t.Errorf("Notes.CreateIssueNote made %d attempts, want 2", attempts)
+	}
+}
+
+func TestDo_retryAfterTooLong(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	client.SetRetryPolicy(testRetryPolicy())
+
+	attempts := 0
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	if _, _, err := client.Users.CurrentUser(); err == nil {
+		t.Errorf("Users.CurrentUser returned no error")
+	}
+	if attempts != 1 {
+		t.Errorf("Users.CurrentUser made %d attempts, want 1", attempts)
+	}
+}
+
+func TestResponse_rateLimit(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Limit", "600")
+		w.Header().Set("RateLimit-Observed", "42")
+		w.Header().Set("RateLimit-Remaining", "558")
+		w.Header().Set("RateLimit-Reset", "1500000000")
+		fmt.Fprint(w, `{"id":1}`)
+	})
+
+	_, resp, err := client.Users.CurrentUser()
+	if err != nil {
+		t.Fatalf("Users.CurrentUser returned error: %v", err)
+	}
+
+	want := RateLimit{Limit: 600, Observed: 42, Remaining: 558, Reset: time.Unix(1500000000, 0)}
+	if resp.RateLimit != want {
+		t.Errorf("Response.RateLimit is %+v, want %+v", resp.RateLimit, want)
+	}
+}
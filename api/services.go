@@ -18,8 +18,6 @@ package gitlab
 
 import (
 	"fmt"
-	"net/url"
-	"time"
 )
 
 // ServicesService handles communication with the services related methods of
@@ -31,16 +29,16 @@ type ServicesService struct {
 }
 
 type Service struct {
-	ID                  *int       `json:"id"`
-	Title               *string    `json:"title"`
-	CreatedAt           *time.Time `json:"created_at"`
-	UpdatedAt           *time.Time `json:"created_at"`
-	Active              *bool      `json:"active"`
-	PushEvents          *bool      `json:"push_events"`
-	IssuesEvents        *bool      `json:"issues_events"`
-	MergeRequestsEvents *bool      `json:"merge_requests_events"`
-	TagPushEvents       *bool      `json:"tag_push_events"`
-	NoteEvents          *bool      `json:"note_events"`
+	ID                  *int    `json:"id"`
+	Title               *string `json:"title"`
+	CreatedAt           *Time   `json:"created_at"`
+	UpdatedAt           *Time   `json:"updated_at"`
+	Active              *bool   `json:"active"`
+	PushEvents          *bool   `json:"push_events"`
+	IssuesEvents        *bool   `json:"issues_events"`
+	MergeRequestsEvents *bool   `json:"merge_requests_events"`
+	TagPushEvents       *bool   `json:"tag_push_events"`
+	NoteEvents          *bool   `json:"note_events"`
 }
 
 // SetGitLabCIServiceOptions represents the available SetGitLabCIService()
@@ -58,15 +56,16 @@ type SetGitLabCIServiceOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/services.html#edit-gitlab-ci-service
 func (s *ServicesService) SetGitLabCIService(
-	pid interface{},
-	opt *SetGitLabCIServiceOptions) (*Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *SetGitLabCIServiceOptions,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/services/gitlab-ci", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/services/gitlab-ci", project)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -83,14 +82,16 @@ func (s *ServicesService) SetGitLabCIService(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/services.html#delete-gitlab-ci-service
-func (s *ServicesService) DeleteGitLabCIService(pid interface{}) (*Response, error) {
-	project, err := parseID(pid)
+func (s *ServicesService) DeleteGitLabCIService(
+	pid ProjectRef,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/services/gitlab-ci", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/services/gitlab-ci", project)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -118,15 +119,16 @@ type SetHipChatServiceOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/services.html#edit-hipchat-service
 func (s *ServicesService) SetHipChatService(
-	pid interface{},
-	opt *SetHipChatServiceOptions) (*Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *SetHipChatServiceOptions,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/services/hipchat", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/services/hipchat", project)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -143,14 +145,16 @@ func (s *ServicesService) SetHipChatService(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/services.html#delete-hipchat-service
-func (s *ServicesService) DeleteHipChatService(pid interface{}) (*Response, error) {
-	project, err := parseID(pid)
+func (s *ServicesService) DeleteHipChatService(
+	pid ProjectRef,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/services/hipchat", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/services/hipchat", project)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -179,15 +183,16 @@ type SetDroneCIServiceOptions struct {
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/services.html#createedit-drone-ci-service
 func (s *ServicesService) SetDroneCIService(
-	pid interface{},
-	opt *SetDroneCIServiceOptions) (*Response, error) {
-	project, err := parseID(pid)
+	pid ProjectRef,
+	opt *SetDroneCIServiceOptions,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/services/drone-ci", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/services/drone-ci", project)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -204,14 +209,16 @@ func (s *ServicesService) SetDroneCIService(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/services.html#delete-drone-ci-service
-func (s *ServicesService) DeleteDroneCIService(pid interface{}) (*Response, error) {
-	project, err := parseID(pid)
+func (s *ServicesService) DeleteDroneCIService(
+	pid ProjectRef,
+	options ...OptionFunc) (*Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, err
 	}
-	u := fmt.Sprintf("projects/%s/services/drone-ci", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/services/drone-ci", project)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -241,14 +248,16 @@ type DroneCIService struct {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/services.html#get-drone-ci-service-settings
-func (s *ServicesService) GetDroneCIService(pid interface{}) (*DroneCIService, *Response, error) {
-	project, err := parseID(pid)
+func (s *ServicesService) GetDroneCIService(
+	pid ProjectRef,
+	options ...OptionFunc) (*DroneCIService, *Response, error) {
+	project, err := parseProject(pid)
 	if err != nil {
 		return nil, nil, err
 	}
-	u := fmt.Sprintf("projects/%s/services/drone-ci", url.QueryEscape(project))
+	u := fmt.Sprintf("projects/%s/services/drone-ci", project)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
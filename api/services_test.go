@@ -21,7 +21,7 @@ func TestSetDroneCIService(t *testing.T) {
 	})
 
 	opt := &SetDroneCIServiceOptions{"t", "u", "true"}
-	_, err := client.Services.SetDroneCIService(1, opt)
+	_, err := client.Services.SetDroneCIService(ProjectID(1), opt)
 
 	if err != nil {
 		t.Fatalf("Services.SetDroneCIService returns an error: %v", err)
@@ -36,7 +36,7 @@ func TestDeleteDroneCIService(t *testing.T) {
 		testMethod(t, r, "DELETE")
 	})
 
-	_, err := client.Services.DeleteDroneCIService(1)
+	_, err := client.Services.DeleteDroneCIService(ProjectID(1))
 
 	if err != nil {
 		t.Fatalf("Services.DeleteDroneCIService returns an error: %v", err)
@@ -53,7 +53,7 @@ func TestGetDroneCIService(t *testing.T) {
 	})
 	want := &DroneCIService{Service: Service{ID: Int(1)}}
 
-	service, _, err := client.Services.GetDroneCIService(1)
+	service, _, err := client.Services.GetDroneCIService(ProjectID(1))
 
 	if err != nil {
 		t.Fatalf("Services.GetDroneCIService returns an error: %v", err)
@@ -16,8 +16,6 @@
 
 package gitlab
 
-import "time"
-
 // SessionService handles communication with the session related methods of
 // the GitLab API.
 //
@@ -36,7 +34,7 @@ type Session struct {
 	Name             string      `json:"name"`
 	PrivateToken     string      `json:"private_token"`
 	Blocked          bool        `json:"blocked"`
-	CreatedAt        time.Time   `json:"created_at"`
+	CreatedAt        Time        `json:"created_at"`
 	Bio              interface{} `json:"bio"`
 	Skype            string      `json:"skype"`
 	Linkedin         string      `json:"linkedin"`
@@ -59,11 +57,13 @@ type GetSessionOptions struct {
 	Password string `url:"password,omitempty" json:"password,omitempty"`
 }
 
-// GetSession logs in to get private token.
+// GetSession logs in to get private token. Only supported by API v3.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/session.html#session
-func (s *SessionService) GetSession(opt *GetSessionOptions) (*Session, *Response, error) {
-	req, err := s.client.NewRequest("POST", "session", opt)
+func (s *SessionService) GetSession(
+	opt *GetSessionOptions,
+	options ...OptionFunc) (*Session, *Response, error) {
+	req, err := s.client.NewRequest("POST", "session", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -16,8 +16,6 @@
 
 package gitlab
 
-import "time"
-
 // SettingsService handles communication with the application SettingsService
 // related methods of the GitLab API.
 //
@@ -36,8 +34,8 @@ type Settings struct {
 	SigninEnabled              bool              `json:"signin_enabled"`
 	GravatarEnabled            bool              `json:"gravatar_enabled"`
 	SignInText                 string            `json:"sign_in_text"`
-	CreatedAt                  time.Time         `json:"created_at"`
-	UpdatedAt                  time.Time         `json:"updated_at"`
+	CreatedAt                  Time              `json:"created_at"`
+	UpdatedAt                  Time              `json:"updated_at"`
 	HomePageURL                string            `json:"home_page_url"`
 	DefaultBranchProtection    int               `json:"default_branch_protection"`
 	TwitterSharingEnabled      bool              `json:"twitter_sharing_enabled"`
@@ -59,8 +57,8 @@ func (s Settings) String() string {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/settings.html#get-current-application.settings
-func (s *SettingsService) GetSettings() (*Settings, *Response, error) {
-	req, err := s.client.NewRequest("GET", "application/settings", nil)
+func (s *SettingsService) GetSettings(options ...OptionFunc) (*Settings, *Response, error) {
+	req, err := s.client.NewRequest("GET", "application/settings", nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -101,8 +99,10 @@ type UpdateSettingsOptions struct {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/settings.html#change-application.settings
-func (s *SettingsService) UpdateSettings(opt *UpdateSettingsOptions) (*Settings, *Response, error) {
-	req, err := s.client.NewRequest("PUT", "application/settings", opt)
+func (s *SettingsService) UpdateSettings(
+	opt *UpdateSettingsOptions,
+	options ...OptionFunc) (*Settings, *Response, error) {
+	req, err := s.client.NewRequest("PUT", "application/settings", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -18,7 +18,6 @@ package gitlab
 
 import (
 	"fmt"
-	"time"
 )
 
 // SystemHooksService handles communication with the system hooks related
@@ -33,9 +32,9 @@ type SystemHooksService struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/system_hooks.html
 type Hook struct {
-	ID        int       `json:"id"`
-	URL       string    `json:"url"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int    `json:"id"`
+	URL       string `json:"url"`
+	CreatedAt Time   `json:"created_at"`
 }
 
 func (h Hook) String() string {
@@ -46,8 +45,8 @@ func (h Hook) String() string {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/system_hooks.html#list-system-hooks
-func (s *SystemHooksService) ListHooks() ([]*Hook, *Response, error) {
-	req, err := s.client.NewRequest("GET", "hooks", nil)
+func (s *SystemHooksService) ListHooks(options ...OptionFunc) ([]*Hook, *Response, error) {
+	req, err := s.client.NewRequest("GET", "hooks", nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -73,8 +72,10 @@ type AddHookOptions struct {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/system_hooks.html#add-new-system-hook-hook
-func (s *SystemHooksService) AddHook(opt *AddHookOptions) (*Hook, *Response, error) {
-	req, err := s.client.NewRequest("POST", "hooks", opt)
+func (s *SystemHooksService) AddHook(
+	opt *AddHookOptions,
+	options ...OptionFunc) (*Hook, *Response, error) {
+	req, err := s.client.NewRequest("POST", "hooks", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -108,10 +109,12 @@ func (h HookEvent) String() string {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/system_hooks.html#test-system-hook
-func (s *SystemHooksService) TestHook(hook int) (*HookEvent, *Response, error) {
+func (s *SystemHooksService) TestHook(
+	hook int,
+	options ...OptionFunc) (*HookEvent, *Response, error) {
 	u := fmt.Sprintf("hooks/%d", hook)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -131,10 +134,10 @@ func (s *SystemHooksService) TestHook(hook int) (*HookEvent, *Response, error) {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/system_hooks.html#delete-system-hook
-func (s *SystemHooksService) DeleteHook(hook int) (*Response, error) {
+func (s *SystemHooksService) DeleteHook(hook int, options ...OptionFunc) (*Response, error) {
 	u := fmt.Sprintf("hooks/%d", hook)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,173 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// dateFormat is the format of the date-only fields, e.g. due dates.
+const dateFormat = "2006-01-02"
+
+// timeFormats are the formats GitLab uses for timestamps. The REST API uses
+// ISO 8601, but webhooks and some API v3 payloads use the format of Ruby.
+var timeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02T15:04:05Z0700",
+	dateFormat,
+}
+
+// Date is a calendar date, like the due date of a milestone. It is encoded
+// as "2006-01-02" in JSON payloads and query strings, and the zero Date as
+// null.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the Date of the given year, month and day, in UTC.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a date in any of the formats used by GitLab. The time of
+// day of timestamps is dropped.
+func ParseDate(s string) (Date, error) {
+	t, err := ParseTime(s)
+	if err != nil {
+		return Date{}, err
+	}
+	return NewDate(t.Date()), nil
+}
+
+// String returns the date formatted as "2006-01-02".
+func (d Date) String() string {
+	return d.Format(dateFormat)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Date) MarshalText() ([]byte, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (d *Date) UnmarshalText(text []byte) (err error) {
+	if len(text) == 0 {
+		*d = Date{}
+		return nil
+	}
+	*d, err = ParseDate(string(text))
+	return err
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Date{}
+		return nil
+	}
+	return d.UnmarshalText(bytes.Trim(data, `"`))
+}
+
+// EncodeValues implements the query.Encoder interface.
+func (d Date) EncodeValues(key string, v *url.Values) error {
+	if !d.IsZero() {
+		v.Set(key, d.String())
+	}
+	return nil
+}
+
+// Time is a timestamp, like the creation time of an issue. It is parsed from
+// any of the formats used by GitLab, and encoded as RFC 3339 in JSON payloads
+// and query strings. The zero Time is encoded as null.
+type Time struct {
+	time.Time
+}
+
+// ParseTime parses a timestamp in any of the formats used by GitLab, e.g.
+// "2016-01-04T15:31:51.081Z" or "2016-01-04 15:31:51 UTC".
+func ParseTime(s string) (Time, error) {
+	for _, layout := range timeFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Time{t}, nil
+		}
+	}
+	return Time{}, fmt.Errorf("gitlab: invalid time %q", s)
+}
+
+// String returns the time formatted as RFC 3339.
+func (t Time) String() string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (t Time) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *Time) UnmarshalText(text []byte) (err error) {
+	if len(text) == 0 {
+		*t = Time{}
+		return nil
+	}
+	*t, err = ParseTime(string(text))
+	return err
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Time{}
+		return nil
+	}
+	return t.UnmarshalText(bytes.Trim(data, `"`))
+}
+
+// EncodeValues implements the query.Encoder interface.
+func (t Time) EncodeValues(key string, v *url.Values) error {
+	if !t.IsZero() {
+		v.Set(key, t.String())
+	}
+	return nil
+}
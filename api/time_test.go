@@ -0,0 +1,90 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2016, time.January, 4, 15, 31, 51, 0, time.UTC)
+
+	for _, s := range []string{
+		"2016-01-04T15:31:51Z",
+		"2016-01-04T16:31:51+01:00",
+		"2016-01-04 15:31:51 UTC",
+		"2016-01-04 16:31:51 +0100",
+		"2016-01-04T16:31:51+0100",
+	} {
+		got, err := ParseTime(s)
+		if err != nil {
+			t.Errorf("ParseTime(%q) returned error: %v", s, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", s, got, want)
+		}
+	}
+
+	if _, err := ParseTime("yesterday"); err == nil {
+		t.Errorf("ParseTime(%q) returned no error", "yesterday")
+	}
+}
+
+func TestTime_JSON(t *testing.T) {
+	var v struct {
+		CreatedAt Time  `json:"created_at"`
+		ExpiresAt *Time `json:"expires_at"`
+		DueDate   *Date `json:"due_date"`
+		StartDate Date  `json:"start_date"`
+	}
+	data := `{"created_at":"2016-01-04 15:31:51 UTC","expires_at":null,"due_date":"2016-02-01","start_date":null}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("Error decoding %s: %v", data, err)
+	}
+	if v.CreatedAt.String() != "2016-01-04T15:31:51Z" || v.ExpiresAt != nil {
+		t.Errorf("Decoded times %v and %v", v.CreatedAt, v.ExpiresAt)
+	}
+	if *v.DueDate != NewDate(2016, time.February, 1) || !v.StartDate.IsZero() {
+		t.Errorf("Decoded dates %v and %v", v.DueDate, v.StartDate)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Error encoding %+v: %v", v, err)
+	}
+	want := `{"created_at":"2016-01-04T15:31:51Z","expires_at":null,"due_date":"2016-02-01","start_date":null}`
+	if string(b) != want {
+		t.Errorf("Encoded %s, want %s", b, want)
+	}
+}
+
+func TestDate_query(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/milestones", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testJsonBody(t, r, values{"title": "v1", "due_date": "2016-02-01"})
+		fmt.Fprint(w, `{"id":1,"due_date":"2016-02-01","created_at":"2016-01-04T15:31:51.081Z"}`)
+	})
+
+	due := NewDate(2016, time.February, 1)
+	m, _, err := client.Milestones.CreateMilestone(ProjectID(1), &CreateMilestoneOptions{Title: "v1", DueDate: &due})
+	if err != nil {
+		t.Fatalf("Milestones.CreateMilestone returned error: %v", err)
+	}
+	if m.DueDate == nil || *m.DueDate != due {
+		t.Errorf("Milestones.CreateMilestone returned due date %v, want %v", m.DueDate, due)
+	}
+
+	v := url.Values{}
+	due.EncodeValues("due_date", &v)
+	Date{}.EncodeValues("start_date", &v)
+	if v.Encode() != "due_date=2016-02-01" {
+		t.Errorf("Encoded query %s, want due_date=2016-02-01", v.Encode())
+	}
+}
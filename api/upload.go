@@ -0,0 +1,185 @@
+//
+// Copyright 2015, Sander van Harmelen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/google/go-querystring/query"
+)
+
+// FormFile represents a file sent in a multipart/form-data request.
+type FormFile struct {
+	// Name of the form field holding the file.
+	Field string
+
+	// Name of the file, as reported to GitLab.
+	Name string
+
+	// Content of the file. It is streamed, not read into memory. When it
+	// implements io.Seeker (like *os.File does), it is rewound to send the
+	// request again, otherwise the request is never retried.
+	Content io.Reader
+}
+
+// NewMultipartRequest creates an API request with a multipart/form-data body,
+// holding the files and the fields of opt. The fields are encoded like query
+// parameters. The body is streamed as it is sent, so files of any size (e.g.
+// project exports) can be uploaded; the request can only be retried if all
+// files implement io.Seeker.
+func (c *Client) NewMultipartRequest(
+	method, path string,
+	opt interface{},
+	files []FormFile,
+	options ...OptionFunc) (*http.Request, error) {
+	fields, err := query.Values(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(c.ctx, method, path, nil, nil, "", options)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary := multipart.NewWriter(ioutil.Discard).Boundary()
+	write := func(w io.Writer) error {
+		return writeMultipart(w, boundary, fields, files)
+	}
+
+	body := newMultipartBody(write)
+	req.Body = body
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+
+	// Files which can seek are rewound to their current offset to send the
+	// body again.
+	offsets := make([]int64, len(files))
+	for i, f := range files {
+		s, ok := f.Content.(io.Seeker)
+		if !ok {
+			return req, nil
+		}
+		if offsets[i], err = s.Seek(0, io.SeekCurrent); err != nil {
+			return req, nil
+		}
+	}
+	var mu sync.Mutex
+	req.GetBody = func() (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// The transport may still be sending the previous body, e.g. when
+		// GitLab answered without reading it, so its goroutine is stopped
+		// before the files it copies are rewound.
+		body.Close()
+
+		for i, f := range files {
+			if _, err := f.Content.(io.Seeker).Seek(offsets[i], io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		body = newMultipartBody(write)
+		return body, nil
+	}
+
+	return req, nil
+}
+
+// writeMultipart writes the multipart/form-data body holding fields and files
+// to w.
+func writeMultipart(w io.Writer, boundary string, fields url.Values, files []FormFile) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range fields[k] {
+			if err := mw.WriteField(k, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range files {
+		fw, err := mw.CreateFormFile(f.Field, f.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(fw, f.Content); err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+// multipartBody is a request body written by a goroutine through a pipe. The
+// goroutine is only started by the first read, so a body which is never sent
+// leaks nothing.
+type multipartBody struct {
+	write func(w io.Writer) error
+	once  sync.Once
+	r     *io.PipeReader
+	done  chan struct{}
+}
+
+func newMultipartBody(write func(w io.Writer) error) *multipartBody {
+	return &multipartBody{write: write}
+}
+
+func (b *multipartBody) start() {
+	r, w := io.Pipe()
+	b.r, b.done = r, make(chan struct{})
+	go func() {
+		defer close(b.done)
+		w.CloseWithError(b.write(w))
+	}()
+}
+
+// Read implements the io.Reader interface.
+func (b *multipartBody) Read(p []byte) (int, error) {
+	b.once.Do(b.start)
+	if b.r == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return b.r.Read(p)
+}
+
+// Close implements the io.Closer interface. It waits for the goroutine to
+// stop reading the files, so they can be rewound safely.
+func (b *multipartBody) Close() error {
+	b.once.Do(func() {})
+	if b.r == nil {
+		return nil
+	}
+	err := b.r.Close()
+	<-b.done
+	return err
+}
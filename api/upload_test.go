@@ -0,0 +1,177 @@
+package gitlab
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testFormFile checks that the multipart request r holds a file named name
+// with the given content in field.
+func testFormFile(t *testing.T, r *http.Request, field, name, content string) {
+	f, h, err := r.FormFile(field)
+	if err != nil {
+		t.Fatalf("Error reading form file %s: %v", field, err)
+	}
+	defer f.Close()
+
+	b, _ := ioutil.ReadAll(f)
+	if h.Filename != name || string(b) != content {
+		t.Errorf("Form file %s is %s with %q, want %s with %q", field, h.Filename, b, name, content)
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/projects/1/uploads", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data; boundary=") {
+			t.Errorf("Content-Type is %s, want multipart/form-data", r.Header.Get("Content-Type"))
+		}
+		testFormFile(t, r, "file", "screenshot.png", "png")
+		fmt.Fprint(w, `{
+			"alt": "screenshot",
+			"url": "/uploads/66dbcd21ec5d24ed6ea225176098d52b/screenshot.png",
+			"markdown": "![screenshot](/uploads/66dbcd21ec5d24ed6ea225176098d52b/screenshot.png)"
+		}`)
+	})
+
+	file, _, err := client.Projects.UploadFile(ProjectID(1), strings.NewReader("png"), "screenshot.png")
+	if err != nil {
+		t.Fatalf("Projects.UploadFile returned error: %v", err)
+	}
+
+	want := &ProjectFile{
+		Alt:      "screenshot",
+		URL:      "/uploads/66dbcd21ec5d24ed6ea225176098d52b/screenshot.png",
+		Markdown: "![screenshot](/uploads/66dbcd21ec5d24ed6ea225176098d52b/screenshot.png)",
+	}
+	if !reflect.DeepEqual(want, file) {
+		t.Errorf("Projects.UploadFile returned %+v, want %+v", file, want)
+	}
+}
+
+func TestUploadAvatar(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	mux.HandleFunc("/groups/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testFormFile(t, r, "avatar", "avatar.png", "png")
+		fmt.Fprint(w, `{"id":1,"avatar_url":"http://localhost/avatar.png"}`)
+	})
+
+	group, _, err := client.Groups.UploadAvatar(1, strings.NewReader("png"), "avatar.png")
+	if err != nil {
+		t.Fatalf("Groups.UploadAvatar returned error: %v", err)
+	}
+	if group.AvatarURL != "http://localhost/avatar.png" {
+		t.Errorf("Groups.UploadAvatar returned %+v", group)
+	}
+}
+
+func TestImportProject(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	attempts := 0
+	mux.HandleFunc("/projects/import", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testFormFile(t, r, "file", "export.tar.gz", "tar.gz")
+		if r.FormValue("path") != "api-project" || r.FormValue("namespace") != "group" {
+			t.Errorf("Form values are %v, want path and namespace", r.MultipartForm.Value)
+		}
+
+		// Throttle the first attempt, to check the body is sent again.
+		if attempts++; attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"id":1,"path":"api-project","import_status":"scheduled"}`)
+	})
+
+	client.SetRetryPolicy(DefaultRetryPolicy())
+
+	opt := &ImportProjectOptions{Path: "api-project", Namespace: "group"}
+	status, _, err := client.Projects.ImportProject(strings.NewReader("tar.gz"), "export.tar.gz", opt)
+	if err != nil {
+		t.Fatalf("Projects.ImportProject returned error: %v", err)
+	}
+
+	want := &ImportStatus{ID: 1, Path: "api-project", ImportStatus: "scheduled"}
+	if !reflect.DeepEqual(want, status) {
+		t.Errorf("Projects.ImportProject returned %+v, want %+v", status, want)
+	}
+	if attempts != 2 {
+		t.Errorf("Projects.ImportProject made %d attempts, want 2", attempts)
+	}
+}
+
+func TestImportProject_notSeekable(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	attempts := 0
+	mux.HandleFunc("/projects/import", func(w http.ResponseWriter, r *http.Request) {
+		testFormFile(t, r, "file", "export.tar.gz", "tar.gz")
+		attempts++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	client.SetRetryPolicy(DefaultRetryPolicy())
+
+	// A MultiReader can't seek, so the streamed archive can't be sent again.
+	archive := io.MultiReader(strings.NewReader("tar.gz"))
+	_, _, err := client.Projects.ImportProject(archive, "export.tar.gz", nil)
+	if err == nil {
+		t.Errorf("Projects.ImportProject returned no error")
+	}
+	if attempts != 1 {
+		t.Errorf("Projects.ImportProject made %d attempts, want 1", attempts)
+	}
+}
+
+func TestImportProject_retryUnread(t *testing.T) {
+	mux, server, client := setup()
+	defer teardown(server)
+
+	content := strings.Repeat("tar.gz", 1<<20)
+	attempts := 0
+	mux.HandleFunc("/projects/import", func(w http.ResponseWriter, r *http.Request) {
+		// Reject the first attempt without reading its body, while it is
+		// still being sent.
+		if attempts++; attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("Error reading form file: %v", err)
+		}
+		defer f.Close()
+		if b, _ := ioutil.ReadAll(f); string(b) != content {
+			t.Errorf("Form file holds %d bytes, want the %d bytes of the archive", len(b), len(content))
+		}
+		fmt.Fprint(w, `{"id":1,"import_status":"scheduled"}`)
+	})
+
+	client.SetRetryPolicy(DefaultRetryPolicy())
+
+	archive := bytes.NewReader([]byte(content))
+	if _, _, err := client.Projects.ImportProject(archive, "export.tar.gz", nil); err != nil {
+		t.Fatalf("Projects.ImportProject returned error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Projects.ImportProject made %d attempts, want 2", attempts)
+	}
+}
@@ -18,7 +18,6 @@ package gitlab
 
 import (
 	"fmt"
-	"time"
 )
 
 // UsersService handles communication with the user related methods of
@@ -33,28 +32,28 @@ type UsersService struct {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html
 type User struct {
-	ID               int        `json:"id"`
-	Username         string     `json:"username"`
-	Email            string     `json:"email"`
-	Name             string     `json:"name"`
-	State            string     `json:"state"`
-	CreatedAt        time.Time  `json:"created_at"`
-	Bio              string     `json:"bio"`
-	Skype            string     `json:"skype"`
-	Linkedin         string     `json:"linkedin"`
-	Twitter          string     `json:"twitter"`
-	WebsiteURL       string     `json:"website_url"`
-	ExternUID        string     `json:"extern_uid"`
-	Provider         string     `json:"provider"`
-	ThemeID          int        `json:"theme_id"`
-	ColorSchemeID    int        `json:"color_scheme_id"`
-	IsAdmin          bool       `json:"is_admin"`
-	AvatarURL        string     `json:"avatar_url"`
-	CanCreateGroup   bool       `json:"can_create_group"`
-	CanCreateProject bool       `json:"can_create_project"`
-	ProjectsLimit    int        `json:"projects_limit"`
-	CurrentSignInAt  *time.Time `json:"current_sign_in_at"`
-	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	ID               int    `json:"id"`
+	Username         string `json:"username"`
+	Email            string `json:"email"`
+	Name             string `json:"name"`
+	State            string `json:"state"`
+	CreatedAt        Time   `json:"created_at"`
+	Bio              string `json:"bio"`
+	Skype            string `json:"skype"`
+	Linkedin         string `json:"linkedin"`
+	Twitter          string `json:"twitter"`
+	WebsiteURL       string `json:"website_url"`
+	ExternUID        string `json:"extern_uid"`
+	Provider         string `json:"provider"`
+	ThemeID          int    `json:"theme_id"`
+	ColorSchemeID    int    `json:"color_scheme_id"`
+	IsAdmin          bool   `json:"is_admin"`
+	AvatarURL        string `json:"avatar_url"`
+	CanCreateGroup   bool   `json:"can_create_group"`
+	CanCreateProject bool   `json:"can_create_project"`
+	ProjectsLimit    int    `json:"projects_limit"`
+	CurrentSignInAt  *Time  `json:"current_sign_in_at"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
 }
 
 // ListUsersOptions represents the available ListUsers() options.
@@ -69,8 +68,10 @@ type ListUsersOptions struct {
 // ListUsers gets a list of users.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#list-users
-func (s *UsersService) ListUsers(opt *ListUsersOptions) ([]*User, *Response, error) {
-	req, err := s.client.NewRequest("GET", "users", opt)
+func (s *UsersService) ListUsers(
+	opt *ListUsersOptions,
+	options ...OptionFunc) ([]*User, *Response, error) {
+	req, err := s.client.NewRequest("GET", "users", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -87,10 +88,10 @@ func (s *UsersService) ListUsers(opt *ListUsersOptions) ([]*User, *Response, err
 // GetUser gets a single user.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#single-user
-func (s *UsersService) GetUser(user int) (*User, *Response, error) {
+func (s *UsersService) GetUser(user int, options ...OptionFunc) (*User, *Response, error) {
 	u := fmt.Sprintf("users/%d", user)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -128,8 +129,10 @@ type CreateUserOptions struct {
 // CreateUser creates a new user. Note only administrators can create new users.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#user-creation
-func (s *UsersService) CreateUser(opt *CreateUserOptions) (*User, *Response, error) {
-	req, err := s.client.NewRequest("POST", "users", opt)
+func (s *UsersService) CreateUser(
+	opt *CreateUserOptions,
+	options ...OptionFunc) (*User, *Response, error) {
+	req, err := s.client.NewRequest("POST", "users", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -167,10 +170,13 @@ type ModifyUserOptions struct {
 // of a user.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#user-modification
-func (s *UsersService) ModifyUser(user int, opt *ModifyUserOptions) (*User, *Response, error) {
+func (s *UsersService) ModifyUser(
+	user int,
+	opt *ModifyUserOptions,
+	options ...OptionFunc) (*User, *Response, error) {
 	u := fmt.Sprintf("users/%d", user)
 
-	req, err := s.client.NewRequest("PUT", u, opt)
+	req, err := s.client.NewRequest("PUT", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -191,10 +197,10 @@ func (s *UsersService) ModifyUser(user int, opt *ModifyUserOptions) (*User, *Res
 // latter not.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#user-deletion
-func (s *UsersService) DeleteUser(user int) (*Response, error) {
+func (s *UsersService) DeleteUser(user int, options ...OptionFunc) (*Response, error) {
 	u := fmt.Sprintf("users/%d", user)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -210,8 +216,8 @@ func (s *UsersService) DeleteUser(user int) (*Response, error) {
 // CurrentUser gets currently authenticated user.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#current-user
-func (s *UsersService) CurrentUser() (*User, *Response, error) {
-	req, err := s.client.NewRequest("GET", "user", nil)
+func (s *UsersService) CurrentUser(options ...OptionFunc) (*User, *Response, error) {
+	req, err := s.client.NewRequest("GET", "user", nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -229,17 +235,17 @@ func (s *UsersService) CurrentUser() (*User, *Response, error) {
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#list-ssh-keys
 type SSHKey struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Key       string    `json:"key"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Key       string `json:"key"`
+	CreatedAt Time   `json:"created_at"`
 }
 
 // ListSSHKeys gets a list of currently authenticated user's SSH keys.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#list-ssh-keys
-func (s *UsersService) ListSSHKeys() ([]*SSHKey, *Response, error) {
-	req, err := s.client.NewRequest("GET", "user/keys", nil)
+func (s *UsersService) ListSSHKeys(options ...OptionFunc) ([]*SSHKey, *Response, error) {
+	req, err := s.client.NewRequest("GET", "user/keys", nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -258,10 +264,12 @@ func (s *UsersService) ListSSHKeys() ([]*SSHKey, *Response, error) {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/users.html#list-ssh-keys-for-user
-func (s *UsersService) ListSSHKeysForUser(user int) ([]*SSHKey, *Response, error) {
+func (s *UsersService) ListSSHKeysForUser(
+	user int,
+	options ...OptionFunc) ([]*SSHKey, *Response, error) {
 	u := fmt.Sprintf("users/%d/keys", user)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -278,10 +286,10 @@ func (s *UsersService) ListSSHKeysForUser(user int) ([]*SSHKey, *Response, error
 // GetSSHKey gets a single key.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#single-ssh-key
-func (s *UsersService) GetSSHKey(kid int) (*SSHKey, *Response, error) {
+func (s *UsersService) GetSSHKey(kid int, options ...OptionFunc) (*SSHKey, *Response, error) {
 	u := fmt.Sprintf("user/keys/%d", kid)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequest("GET", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -306,8 +314,10 @@ type AddSSHKeyOptions struct {
 // AddSSHKey creates a new key owned by the currently authenticated user.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#add-ssh-key
-func (s *UsersService) AddSSHKey(opt *AddSSHKeyOptions) (*SSHKey, *Response, error) {
-	req, err := s.client.NewRequest("POST", "user/keys", opt)
+func (s *UsersService) AddSSHKey(
+	opt *AddSSHKeyOptions,
+	options ...OptionFunc) (*SSHKey, *Response, error) {
+	req, err := s.client.NewRequest("POST", "user/keys", opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -327,10 +337,11 @@ func (s *UsersService) AddSSHKey(opt *AddSSHKeyOptions) (*SSHKey, *Response, err
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#add-ssh-key-for-user
 func (s *UsersService) AddSSHKeyForUser(
 	user int,
-	opt *AddSSHKeyOptions) (*SSHKey, *Response, error) {
+	opt *AddSSHKeyOptions,
+	options ...OptionFunc) (*SSHKey, *Response, error) {
 	u := fmt.Sprintf("users/%d/keys", user)
 
-	req, err := s.client.NewRequest("POST", u, opt)
+	req, err := s.client.NewRequest("POST", u, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -350,10 +361,10 @@ func (s *UsersService) AddSSHKeyForUser(
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/users.html#delete-ssh-key-for-current-owner
-func (s *UsersService) DeleteSSHKey(kid int) (*Response, error) {
+func (s *UsersService) DeleteSSHKey(kid int, options ...OptionFunc) (*Response, error) {
 	u := fmt.Sprintf("user/keys/%d", kid)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -371,10 +382,13 @@ func (s *UsersService) DeleteSSHKey(kid int) (*Response, error) {
 //
 // GitLab API docs:
 // http://doc.gitlab.com/ce/api/users.html#delete-ssh-key-for-given-user
-func (s *UsersService) DeleteSSHKeyForUser(user int, kid int) (*Response, error) {
+func (s *UsersService) DeleteSSHKeyForUser(
+	user int,
+	kid int,
+	options ...OptionFunc) (*Response, error) {
 	u := fmt.Sprintf("users/%d/keys/%d", user, kid)
 
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequest("DELETE", u, nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -390,10 +404,10 @@ func (s *UsersService) DeleteSSHKeyForUser(user int, kid int) (*Response, error)
 // BlockUser blocks the specified user. Available only for admin.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#block-user
-func (s *UsersService) BlockUser(user int) (*User, *Response, error) {
+func (s *UsersService) BlockUser(user int, options ...OptionFunc) (*User, *Response, error) {
 	u := fmt.Sprintf("users/%d/block", user)
 
-	req, err := s.client.NewRequest("PUT", u, nil)
+	req, err := s.client.NewRequest("PUT", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -410,10 +424,10 @@ func (s *UsersService) BlockUser(user int) (*User, *Response, error) {
 // UnblockUser unblocks the specified user. Available only for admin.
 //
 // GitLab API docs: http://doc.gitlab.com/ce/api/users.html#unblock-user
-func (s *UsersService) UnblockUser(user int) (*User, *Response, error) {
+func (s *UsersService) UnblockUser(user int, options ...OptionFunc) (*User, *Response, error) {
 	u := fmt.Sprintf("users/%d/unblock", user)
 
-	req, err := s.client.NewRequest("PUT", u, nil)
+	req, err := s.client.NewRequest("PUT", u, nil, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,9 +1,15 @@
 package gitlab
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"expvar"
 	"fmt"
+	"html"
+	"io"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +21,40 @@ import (
 
 var m = integram.HTMLRichText{}
 
+// apiCache holds the most recent API responses of all users, revalidated
+// with conditional requests
+var apiCache = api.NewMemoryCache(apiCacheSize)
+
+// Responses kept by apiCache, so a long-running bot doesn't grow without bound
+const apiCacheSize = 5000
+
+// Requests per second and burst allowed per GitLab instance, before GitLab
+// reports its own rate limit status
+const (
+	apiRateLimit = 10
+	apiRateBurst = 20
+)
+
+// Lines of the trace posted along with a failed job, read from its last
+// traceTailBytes bytes and cut to traceTailChars characters, so the message
+// stays under the 4096 characters allowed by Telegram
+const (
+	traceTailLines = 10
+	traceTailBytes = 16 << 10
+	traceTailChars = 3000
+)
+
+// ansiEscape matches the color and cursor sequences of job traces
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// apiMetrics counts the API calls of all users per endpoint, published as the
+// gitlab_api expvar
+var apiMetrics = api.NewMetrics()
+
+func init() {
+	expvar.Publish("gitlab_api", apiMetrics)
+}
+
 //Config contains OAuth data only
 type Config struct {
 	integram.OAuthProvider
@@ -67,7 +107,7 @@ func chatSettings(c *integram.Context) ChatSettings {
 	return s
 }
 
-const apiSuffixURL = "/api/v3/"
+const apiSuffixURL = "/api/v4/"
 
 // Service returns integram.Service from gitlab.Config
 func (c Config) Service() *integram.Service {
@@ -89,6 +129,7 @@ func (c Config) Service() *integram.Service {
 			hostedAppIDEntered,
 			hostedAppSecretEntered,
 			issueReplied,
+			issueRepliedIID,
 			mrReplied,
 			snippetReplied,
 			commitReplied,
@@ -170,7 +211,7 @@ type user struct {
 type commit struct {
 	ID        string `json:"id"`
 	Message   string
-	Timestamp time.Time
+	Timestamp api.Time
 	Author    author
 	URL       string `json:"url"`
 	Added     []string
@@ -181,7 +222,7 @@ type commit struct {
 type commitWithoutID struct {
 	SHA         string `json:"sha"`
 	Message     string
-	Timestamp   time.Time
+	Timestamp   api.Time
 	Author      author
 	AuthorName  string `json:"author_name"`
 	AuthorEmail string `json:"author_email"`
@@ -195,13 +236,13 @@ type attributes struct {
 	ID           int `json:"id"`
 	Title        string
 	Note         string
-	NoteableType string `json:"noteable_type"`
-	AssigneeID   int    `json:"assignee_id"`
-	AuthorID     int    `json:"author_id"`
-	ProjectID    int    `json:"project_id"`
-	CreatedAt    string `json:"created_at"`
-	UpdatedAt    string `json:"updated_at"`
-	CommitID     string `json:"commit_id"`
+	NoteableType string   `json:"noteable_type"`
+	AssigneeID   int      `json:"assignee_id"`
+	AuthorID     int      `json:"author_id"`
+	ProjectID    int      `json:"project_id"`
+	CreatedAt    api.Time `json:"created_at"`
+	UpdatedAt    api.Time `json:"updated_at"`
+	CommitID     string   `json:"commit_id"`
 	Position     int
 	BranchName   string `json:"branch_name"`
 	Description  string
@@ -253,6 +294,7 @@ type webhook struct {
 	User             user
 	UserID           int         `json:"user_id"`
 	UserName         string      `json:"user_name"`
+	UserUsername     string      `json:"user_username"`
 	UserEmail        string      `json:"user_email"`
 	UserAvatar       string      `json:"user_avatar"`
 	ObjectAttributes *attributes `json:"object_attributes"`
@@ -280,6 +322,15 @@ func mention(c *integram.Context, name string, email string) string {
 	return "@" + userName
 }
 
+// username returns the username of the user who triggered the event. Older
+// GitLab versions only send the name with push events, so fall back to it.
+func (wh *webhook) username() string {
+	if wh.UserUsername != "" {
+		return wh.UserUsername
+	}
+	return wh.UserName
+}
+
 func compareURL(home string, before string, after string) string {
 	return home + "/compare/" + before + "..." + after
 }
@@ -320,6 +371,22 @@ func snippetMessageID(c *integram.Context, snippetID int) int {
 	return 0
 }
 
+// isInvalidGrant reports whether err is the invalid_grant error GitLab returns
+// when exchanging an invalid code for an existing application.
+func isInvalidGrant(err error) bool {
+	var rerr *oauth2.RetrieveError
+	if !errors.As(err, &rerr) {
+		return false
+	}
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.Unmarshal(rerr.Body, &body)
+
+	return body.Error == "invalid_grant"
+}
+
 func hostedAppSecretEntered(c *integram.Context, baseURL string, appID string) error {
 	c.SetServiceBaseURL(baseURL)
 
@@ -336,7 +403,7 @@ func hostedAppSecretEntered(c *integram.Context, baseURL string, appID string) e
 	conf := integram.OAuthProvider{BaseURL: c.ServiceBaseURL, ID: appID, Secret: appSecret}
 	_, err := conf.OAuth2Client(c).Exchange(oauth2.NoContext, "-")
 
-	if strings.Contains(err.Error(), `"error":"invalid_grant"`) {
+	if isInvalidGrant(err) {
 		// means the app is exists
 		c.SaveOAuthProvider(c.ServiceBaseURL, appID, appSecret)
 		_, err := mustBeAuthed(c)
@@ -398,15 +465,145 @@ func noteUniqueID(projectID int, noteID string) string {
 	return "note_" + strconv.Itoa(projectID) + "_" + noteID
 }
 
+// commitNoteID identifies a commit note by its creation time, as commit notes
+// have no ID in the API. The API and webhooks format it differently, so it is
+// normalized to seconds in UTC
+func commitNoteID(createdAt api.Time) string {
+	return createdAt.UTC().Format(time.RFC3339)
+}
+
+// legacyCommitNoteLayout is the layout of the created_at of notes sent by the
+// API, which was stored verbatim as the commit note ID before commitNoteID.
+const legacyCommitNoteLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// commitNoteIDs returns the IDs a commit note may have been stored with: the
+// commitNoteID, then the created_at of the API as older versions stored it, in
+// the time zone of createdAt and in UTC. Their milliseconds are only found when
+// createdAt holds them.
+func commitNoteIDs(createdAt api.Time) []string {
+	ids := []string{commitNoteID(createdAt)}
+	for _, t := range []time.Time{createdAt.Time, createdAt.UTC()} {
+		id := t.Format(legacyCommitNoteLayout)
+		if id != ids[len(ids)-1] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// noteEventIDs returns the event IDs the message of the note of a webhook may
+// have been stored with, when the note was sent from the chat
+func noteEventIDs(wh *webhook) []string {
+	noteIDs := []string{strconv.Itoa(wh.ObjectAttributes.ID)}
+	if wh.ObjectAttributes.NoteableType == "Commit" {
+		// collisions by date are unlikely here
+		noteIDs = commitNoteIDs(wh.ObjectAttributes.CreatedAt)
+	}
+
+	eventIDs := make([]string, len(noteIDs))
+	for i, noteID := range noteIDs {
+		eventIDs[i] = noteUniqueID(wh.ObjectAttributes.ProjectID, noteID)
+	}
+	return eventIDs
+}
+
 func client(c *integram.Context) *api.Client {
 
+	// The OAuth HTTP client adds the bearer token (and refreshes it), so the
+	// API client itself sends no credentials.
 	client := api.NewClient(c.User.OAuthHTTPClient(), "")
 	client.SetBaseURL(c.ServiceBaseURL.String() + apiSuffixURL)
+	client.SetRetryPolicy(api.DefaultRetryPolicy())
+	// All users of an instance share its rate limit, so spread the jobs out
+	client.SetRateLimit(apiRateLimit, apiRateBurst)
+	// The credentials live in the OAuth HTTP client, so keep users apart
+	client.SetCache(api.PrefixCache(fmt.Sprintf("%v:", c.User.ID), apiCache))
+	client.Use(apiMetrics.Middleware())
+	// Logged at debug level, with the tokens of the user redacted
+	client.SetLogger(api.LoggerFunc(func(msg string, keyvals ...interface{}) {
+		entry := c.Log()
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			entry = entry.WithField(fmt.Sprint(keyvals[i]), keyvals[i+1])
+		}
+		entry.Debug(msg)
+	}))
 	return client
 }
 
+// jobTraceTail returns the last lines of the trace of a job, without the ANSI
+// escape sequences, so a failure can be read without leaving the chat
+func jobTraceTail(c *integram.Context, projectID int, jobID int) string {
+	trace, _, err := client(c).Jobs.JobTraceReader(api.ProjectID(projectID), jobID)
+	if err != nil {
+		c.Log().WithError(err).Warn("can't get the trace of the job")
+		return ""
+	}
+	defer trace.Close()
+
+	tail, err := readTail(trace, traceTailBytes)
+	if err != nil {
+		c.Log().WithError(err).Warn("can't read the trace of the job")
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(ansiEscape.ReplaceAllString(string(tail), "")), "\n")
+	if len(lines) > traceTailLines {
+		lines = lines[len(lines)-traceTailLines:]
+	}
+	text := []rune(strings.Join(lines, "\n"))
+	if len(text) > traceTailChars {
+		return "…" + string(text[len(text)-traceTailChars:])
+	}
+	return string(text)
+}
+
+// readTail returns the last n bytes of r, starting at a line, without keeping
+// more than 2n bytes in memory
+func readTail(r io.Reader, n int) ([]byte, error) {
+	buf := make([]byte, 2*n)
+	size := 0
+	truncated := false
+	for {
+		if size == len(buf) {
+			size = copy(buf, buf[size-n:])
+			truncated = true
+		}
+		read, err := r.Read(buf[size:])
+		size += read
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tail := buf[:size]
+	if size > n {
+		tail, truncated = tail[size-n:], true
+	}
+	// The first line was cut, drop it
+	if i := bytes.IndexByte(tail, '\n'); truncated && i >= 0 {
+		tail = tail[i+1:]
+	}
+	return tail, nil
+}
+
+// releaseNotes returns the release notes of a tag, if any
+func releaseNotes(c *integram.Context, projectID int, tagName string) string {
+	tag, _, err := client(c).Repositories.GetTag(api.ProjectID(projectID), tagName)
+	if err != nil {
+		c.Log().WithError(err).Warn("can't get the tag")
+		return ""
+	}
+	if tag.Release == nil {
+		return ""
+	}
+	return strings.TrimSpace(tag.Release.Description)
+}
+
 func sendIssueComment(c *integram.Context, projectID int, issueID int, text string) error {
-	note, _, err := client(c).Notes.CreateIssueNote(projectID, issueID, &api.CreateIssueNoteOptions{Body: text})
+	note, _, err := client(c).Notes.CreateIssueNote(api.ProjectID(projectID), issueID, &api.CreateIssueNoteOptions{Body: text})
 
 	if note != nil {
 		c.Message.UpdateEventsID(c.Db(), "issue_note_"+strconv.Itoa(note.ID))
@@ -416,7 +613,7 @@ func sendIssueComment(c *integram.Context, projectID int, issueID int, text stri
 }
 
 func sendMRComment(c *integram.Context, projectID int, MergeRequestID int, text string) error {
-	note, _, err := client(c).Notes.CreateMergeRequestNote(projectID, MergeRequestID, &api.CreateMergeRequestNoteOptions{Body: text})
+	note, _, err := client(c).Notes.CreateMergeRequestNote(api.ProjectID(projectID), MergeRequestID, &api.CreateMergeRequestNoteOptions{Body: text})
 
 	if note != nil {
 		c.Message.UpdateEventsID(c.Db(), noteUniqueID(projectID, strconv.Itoa(note.ID)))
@@ -426,7 +623,7 @@ func sendMRComment(c *integram.Context, projectID int, MergeRequestID int, text
 }
 
 func sendSnippetComment(c *integram.Context, projectID int, SnippetID int, text string) error {
-	note, _, err := client(c).Notes.CreateSnippetNote(projectID, SnippetID, &api.CreateSnippetNoteOptions{Body: text})
+	note, _, err := client(c).Notes.CreateSnippetNote(api.ProjectID(projectID), SnippetID, &api.CreateSnippetNoteOptions{Body: text})
 	if note != nil {
 		c.Message.UpdateEventsID(c.Db(), noteUniqueID(projectID, strconv.Itoa(note.ID)))
 	}
@@ -442,12 +639,12 @@ func trim(s string, max int) string {
 }
 
 func sendCommitComment(c *integram.Context, projectID int, commitID string, msg *integram.IncomingMessage) error {
-	note, _, err := client(c).Notes.CreateCommitNote(projectID, commitID, &api.CreateCommitNoteOptions{Note: msg.Text})
+	note, _, err := client(c).Notes.CreateCommitNote(api.ProjectID(projectID), commitID, &api.CreateCommitNoteOptions{Note: msg.Text})
 	if err != nil {
 		return err
 	}
 	// note id not available for commit comment. So use the date. Collisions are unlikely here...
-	c.Message.UpdateEventsID(c.Db(), noteUniqueID(projectID, note.CreatedAt))
+	c.Message.UpdateEventsID(c.Db(), noteUniqueID(projectID, commitNoteID(note.CreatedAt)))
 
 	return err
 }
@@ -507,17 +704,62 @@ func commitReplied(c *integram.Context, baseURL string, projectID int, commitID
 	return err
 }
 
+// issueReplied handles the replies to the issue messages sent with API v3,
+// whose reply actions hold the global ID of the issue instead of its IID
 func issueReplied(c *integram.Context, baseURL string, projectID int, issueID int) error {
 	c.SetServiceBaseURL(baseURL)
 
 	authorized, err := mustBeAuthed(c)
 	if !authorized {
 		c.User.SetAfterAuthAction(issueReplied, baseURL, projectID, issueID)
+		c.Message.SetReplyAction(issueReplied, baseURL, projectID, issueID)
+		return err
+	}
+
+	issueIID, err := legacyIssueIID(c, projectID, issueID)
+	if err != nil {
+		c.Message.SetReplyAction(issueReplied, baseURL, projectID, issueID)
+		return err
+	}
+	return issueRepliedIID(c, baseURL, projectID, issueIID)
+}
+
+// legacyIssueIID returns the IID of the issue of a project with the given
+// global ID. Issues can't be filtered by ID, so they are searched page by page
+func legacyIssueIID(c *integram.Context, projectID int, issueID int) (int, error) {
+	git := client(c)
+	opt := &api.ListProjectIssuesOptions{}
+	fetch := func(page api.ListOptions) ([]*api.Issue, *api.Response, error) {
+		opt.ListOptions = page
+		return git.Issues.ListProjectIssues(api.ProjectID(projectID), opt)
+	}
+
+	issueIID := 0
+	err := api.ForEach(fetch, &api.PaginationOptions{PerPage: 100}, func(issue *api.Issue) bool {
+		if issue.ID == issueID {
+			issueIID = issue.IID
+		}
+		return issueIID == 0
+	})
+	if err == nil && issueIID == 0 {
+		err = fmt.Errorf("issue %d not found in project %d", issueID, projectID)
+	}
+	return issueIID, err
+}
+
+// issueRepliedIID handles the replies to issue messages, API v4 identifies
+// issues by their IID
+func issueRepliedIID(c *integram.Context, baseURL string, projectID int, issueIID int) error {
+	c.SetServiceBaseURL(baseURL)
+
+	authorized, err := mustBeAuthed(c)
+	if !authorized {
+		c.User.SetAfterAuthAction(issueRepliedIID, baseURL, projectID, issueIID)
 	} else {
-		_, err = c.Service().DoJob(sendIssueComment, c, projectID, issueID, c.Message.Text)
+		_, err = c.Service().DoJob(sendIssueComment, c, projectID, issueIID, c.Message.Text)
 	}
 
-	c.Message.SetReplyAction(issueReplied, baseURL, projectID, issueID)
+	c.Message.SetReplyAction(issueRepliedIID, baseURL, projectID, issueIID)
 	return err
 }
 
@@ -642,7 +884,7 @@ func webhookHandler(c *integram.Context, request *integram.WebhookContext) (err
 
 			text := fmt.Sprintf(
 				"%s %s to %s\n%s",
-				mention(c, wh.UserName, wh.UserEmail),
+				mention(c, wh.username(), wh.UserEmail),
 				m.URL("pushed", wp),
 				m.URL(destStr+"/"+branch, wh.Repository.Homepage+"/tree/"+url.QueryEscape(branch)),
 				text,
@@ -656,11 +898,11 @@ func webhookHandler(c *integram.Context, request *integram.WebhookContext) (err
 
 		} else {
 			if wh.After != "0000000000000000000000000000000000000000" && wh.After != "" {
-				err = msg.SetText(fmt.Sprintf("%s created branch %s\n%s", mention(c, wh.UserName, wh.UserEmail), m.URL(wh.Repository.Name+"/"+branch, wh.Repository.Homepage+"/tree/"+url.QueryEscape(branch)), text)).
+				err = msg.SetText(fmt.Sprintf("%s created branch %s\n%s", mention(c, wh.username(), wh.UserEmail), m.URL(wh.Repository.Name+"/"+branch, wh.Repository.Homepage+"/tree/"+url.QueryEscape(branch)), text)).
 					EnableHTML().
 					Send()
 			} else {
-				err = msg.SetText(fmt.Sprintf("%s deleted branch %s\n%s", mention(c, wh.UserName, wh.UserEmail), m.Bold(wh.Repository.Name+"/"+branch), text)).
+				err = msg.SetText(fmt.Sprintf("%s deleted branch %s\n%s", mention(c, wh.username(), wh.UserEmail), m.Bold(wh.Repository.Name+"/"+branch), text)).
 					EnableHTML().
 					Send()
 			}
@@ -681,14 +923,19 @@ func webhookHandler(c *integram.Context, request *integram.WebhookContext) (err
 			destStr = wh.UserName + " / " + wh.Repository.Name
 		}
 
-		return msg.SetText(
-			fmt.Sprintf(
-				"%s pushed new %s at %s",
-				mention(c, wh.UserName, wh.UserEmail),
-				m.URL(itemType+" "+s[len(s)-1], wh.Repository.Homepage+"/tree/"+s[len(s)-1]),
-				m.URL(destStr, wh.Repository.Homepage),
-			),
-		).EnableHTML().DisableWebPreview().Send()
+		text := fmt.Sprintf(
+			"%s pushed new %s at %s",
+			mention(c, wh.username(), wh.UserEmail),
+			m.URL(itemType+" "+s[len(s)-1], wh.Repository.Homepage+"/tree/"+s[len(s)-1]),
+			m.URL(destStr, wh.Repository.Homepage),
+		)
+		if itemType == "tag" && wh.After != "0000000000000000000000000000000000000000" && wh.After != "" {
+			if notes := releaseNotes(c, wh.ProjectID, s[len(s)-1]); notes != "" {
+				text += "\n" + html.EscapeString(notes)
+			}
+		}
+
+		return msg.SetText(text).EnableHTML().DisableWebPreview().Send()
 	case "issue":
 		if wh.ObjectAttributes.MilestoneID > 0 {
 			// Todo: need an API access to fetch milestones
@@ -711,7 +958,7 @@ func webhookHandler(c *integram.Context, request *integram.WebhookContext) (err
 			return nil
 		}
 
-		msg.SetReplyAction(issueReplied, c.ServiceBaseURL.String(), wh.ObjectAttributes.ProjectID, wh.ObjectAttributes.ID)
+		msg.SetReplyAction(issueRepliedIID, c.ServiceBaseURL.String(), wh.ObjectAttributes.ProjectID, wh.ObjectAttributes.Iid)
 
 		if wh.ObjectAttributes.Action == "open" {
 			return msg.AddEventID("issue_" + strconv.Itoa(wh.ObjectAttributes.ID)).SetText(fmt.Sprintf("%s %s %s at %s:\n%s\n%s", mention(c, wh.User.Username, wh.UserEmail), wh.ObjectAttributes.State, m.URL("issue", wh.ObjectAttributes.URL), m.URL(wh.User.Username+" / "+wh.Repository.Name, wh.Repository.Homepage), m.Bold(wh.ObjectAttributes.Title), wh.ObjectAttributes.Description)).
@@ -740,13 +987,10 @@ func webhookHandler(c *integram.Context, request *integram.WebhookContext) (err
 		wp := ""
 		noteType := ""
 		originMsg := &integram.Message{}
-		noteID := strconv.Itoa(wh.ObjectAttributes.ID)
-		if wh.ObjectAttributes.Note == "Commit" {
-			// collisions by date are unlikely here
-			noteID = wh.ObjectAttributes.CreatedAt
-		}
-		if msg, _ := c.FindMessageByEventID(noteUniqueID(wh.ObjectAttributes.ProjectID, noteID)); msg != nil {
-			return nil
+		for _, eventID := range noteEventIDs(wh) {
+			if msg, _ := c.FindMessageByEventID(eventID); msg != nil {
+				return nil
+			}
 		}
 
 		switch wh.ObjectAttributes.NoteableType {
@@ -888,6 +1132,11 @@ func webhookHandler(c *integram.Context, request *integram.WebhookContext) (err
 
 		cs := chatSettings(c)
 		if cs.CI.Success && (wh.BuildStatus == "success") || cs.CI.Cancel && (wh.BuildStatus == "canceled") || cs.CI.Fail && (wh.BuildStatus == "failed") && !wh.BuildAllowFailure {
+			if wh.BuildStatus == "failed" {
+				if trace := jobTraceTail(c, wh.ProjectID, wh.BuildID); trace != "" {
+					text += "\n" + m.Pre(trace)
+				}
+			}
 			return msg.SetText(text).
 				EnableHTML().Send()
 		}
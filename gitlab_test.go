@@ -0,0 +1,45 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNoteEventIDs(t *testing.T) {
+	var wh webhook
+	payload := `{"object_kind":"note","object_attributes":{"id":1244,"note":"Nice","noteable_type":"Issue","project_id":5}}`
+	if err := json.Unmarshal([]byte(payload), &wh); err != nil {
+		t.Fatalf("Error decoding the webhook: %v", err)
+	}
+
+	if got, want := noteEventIDs(&wh), []string{"note_5_1244"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("noteEventIDs returned %v, want %v", got, want)
+	}
+}
+
+func TestNoteEventIDs_legacyCommitNote(t *testing.T) {
+	// Older versions stored the created_at of the note sent by the API
+	stored := map[string]bool{
+		noteUniqueID(5, "2016-01-19T09:44:55.000Z"): true,
+	}
+
+	var wh webhook
+	payload := `{"object_kind":"note","object_attributes":{"id":1244,"note":"This is a commit comment","noteable_type":"Commit","project_id":5,"created_at":"2016-01-19 09:44:55 UTC","commit_id":"cfe32cf6"}}`
+	if err := json.Unmarshal([]byte(payload), &wh); err != nil {
+		t.Fatalf("Error decoding the webhook: %v", err)
+	}
+
+	ids := noteEventIDs(&wh)
+	if len(ids) == 0 || ids[0] != "note_5_2016-01-19T09:44:55Z" {
+		t.Errorf("noteEventIDs returned %v, want note_5_2016-01-19T09:44:55Z first", ids)
+	}
+
+	found := false
+	for _, id := range ids {
+		found = found || stored[id]
+	}
+	if !found {
+		t.Errorf("noteEventIDs returned %v, want the legacy event ID of the stored message", ids)
+	}
+}